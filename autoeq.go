@@ -0,0 +1,315 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"ahtvc/eq"
+)
+
+// 웹 검색 결과 최대 개수
+const maxDeviceResults = 50
+
+// AutoEQ 저장소의 기기 하나 (기기 폴더의 GraphicEQ 또는 CSV 결과 파일)
+type autoEQDevice struct {
+	Name   string        `json:"name"`   // 기기 이름 (브랜드 모델 (구분))
+	Source string        `json:"source"` // 결과 폴더 (측정 출처/타겟, 예: oratory1990/harman_over-ear_2018)
+	Path   string        `json:"path"`   // 저장소 기준 상대 경로 (/ 구분)
+	Info   eq.DeviceInfo `json:"info"`   // 경로에서 읽은 기기 정보
+	key    string        // 검색용 키 (소문자, 영문/숫자만)
+}
+
+// 로컬 AutoEQ 저장소 기기 색인
+type autoEQIndex struct {
+	root    string // 색인한 폴더 (저장소의 results 폴더가 있으면 그 폴더)
+	devices []autoEQDevice
+	byPath  map[string]int
+}
+
+// 로컬 AutoEQ 저장소 (git clone) 를 훑어 기기 색인 생성
+// 기기 폴더마다 "<기기> GraphicEQ.txt" 를 우선 사용하고, 없으면 AutoEQ CSV 를 사용합니다.
+func buildAutoEQIndex(repoDir string) (*autoEQIndex, error) {
+	root := repoDir
+	if info, err := os.Stat(filepath.Join(repoDir, "results")); err == nil && info.IsDir() {
+		root = filepath.Join(repoDir, "results")
+	}
+	best := make(map[string]string) // 기기 폴더 -> 결과 파일
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rank := autoEQFileRank(d.Name())
+		if rank < 0 {
+			return nil
+		}
+		dir := filepath.Dir(p)
+		if current, ok := best[dir]; !ok || rank < autoEQFileRank(filepath.Base(current)) {
+			best[dir] = p
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := &autoEQIndex{root: root, byPath: make(map[string]int)}
+	for dir, file := range best {
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return nil, err
+		}
+		source, _ := filepath.Rel(root, filepath.Dir(dir))
+		if source == "." {
+			source = ""
+		}
+		info := eq.ParseDeviceInfo(filepath.ToSlash(rel))
+		name := info.FileStem()
+		if strings.Trim(info.Name(), ". ") == "" {
+			name = filepath.Base(dir)
+		}
+		device := autoEQDevice{
+			Name:   name,
+			Source: filepath.ToSlash(source),
+			Path:   filepath.ToSlash(rel),
+			Info:   info,
+		}
+		device.key = searchKey(device.Name + " " + device.Source)
+		index.devices = append(index.devices, device)
+	}
+	sort.Slice(index.devices, func(i, j int) bool {
+		a, b := index.devices[i], index.devices[j]
+		if !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.Path < b.Path
+	})
+	for i, device := range index.devices {
+		index.byPath[device.Path] = i
+	}
+	return index, nil
+}
+
+// 기기 결과 파일 우선순위 (작을수록 우선, -1 이면 사용하지 않음)
+func autoEQFileRank(name string) int {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, " graphiceq.txt"):
+		return 0
+	case strings.HasSuffix(lower, ".csv"):
+		return 1
+	default:
+		return -1
+	}
+}
+
+// 검색용 키 (소문자, 영문/숫자만 남김: "HD 650" 과 "hd650" 이 같은 키)
+func searchKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Len 은 색인된 기기 수입니다.
+func (idx *autoEQIndex) Len() int {
+	return len(idx.devices)
+}
+
+// 이름/출처로 기기 검색 (검색어의 모든 단어 포함, 이름이 정확히 같거나 이름으로 시작하는 기기 우선)
+func (idx *autoEQIndex) Search(query string, limit int) []autoEQDevice {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if term := searchKey(field); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	whole := searchKey(query)
+	var exact, prefix, rest []autoEQDevice
+	for _, device := range idx.devices {
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(device.key, term) {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		name := searchKey(device.Name)
+		switch {
+		case name == whole:
+			exact = append(exact, device)
+		case strings.HasPrefix(name, whole):
+			prefix = append(prefix, device)
+		default:
+			rest = append(rest, device)
+		}
+	}
+	results := append(append(exact, prefix...), rest...)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// 상대 경로로 기기 찾기 (색인에 있는 경로만 허용)
+func (idx *autoEQIndex) Device(relPath string) (autoEQDevice, error) {
+	if idx == nil {
+		return autoEQDevice{}, errorf("AutoEQ 저장소가 설정되지 않았습니다 (-autoeq)")
+	}
+	i, ok := idx.byPath[relPath]
+	if !ok {
+		return autoEQDevice{}, notFoundf("AutoEQ 기기를 찾을 수 없습니다: '%s'", relPath)
+	}
+	return idx.devices[i], nil
+}
+
+// 기기 결과 파일 읽기 (파일 이름, 내용)
+func (idx *autoEQIndex) Read(device autoEQDevice) (string, []byte, error) {
+	content, err := os.ReadFile(filepath.Join(idx.root, filepath.FromSlash(device.Path)))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validateTextContent(content); err != nil {
+		return "", nil, err
+	}
+	return path.Base(device.Path), content, nil
+}
+
+// 시작 시 색인한 AutoEQ 저장소 (nil 이면 사용 안 함)
+var activeAutoEQ *autoEQIndex
+
+// 화면/JSON 응답에 기기 검색 결과와 선택한 기기 추가 (?q=검색어, ?device=경로)
+func addDeviceData(r *http.Request, resultData map[string]interface{}) error {
+	if activeAutoEQ == nil {
+		return nil
+	}
+	if query := strings.TrimSpace(r.URL.Query().Get("q")); query != "" {
+		resultData["DeviceQuery"] = query
+		resultData["Devices"] = activeAutoEQ.Search(query, maxDeviceResults)
+	}
+	if relPath := r.URL.Query().Get("device"); relPath != "" {
+		device, err := activeAutoEQ.Device(relPath)
+		if err != nil {
+			return err
+		}
+		resultData["Device"] = device
+	}
+	return nil
+}
+
+// CLI AutoEQ 저장소 모드: ahtvc autoeq -repo DIR [-out DIR] [-pick N] 검색어
+// -out 이 없으면 검색 결과만 출력하고, 있으면 선택한 기기를 변환해 저장합니다.
+func runAutoEQCLI(args []string) int {
+	fs := flag.NewFlagSet("autoeq", flag.ContinueOnError)
+	repoDir := fs.String("repo", strings.TrimSpace(os.Getenv(envAutoEQDir)), "로컬 AutoEQ 저장소 폴더 (환경 변수 "+envAutoEQDir+")")
+	outDir := fs.String("out", "", "변환 결과를 저장할 폴더 (비어 있으면 검색만)")
+	pick := fs.Int("pick", 0, "검색 결과 중 변환할 기기 번호 (1부터)")
+	limit := fs.Int("limit", maxDeviceResults, "출력할 검색 결과 최대 개수 (0이면 제한 없음)")
+	pipelineName := fs.String("pipeline", "", "사용할 파이프라인 이름 (비어 있으면 첫 번째)")
+	pipelinesPath := fs.String("pipelines", strings.TrimSpace(os.Getenv(envPipelines)), "파이프라인 정의 JSON 파일 (환경 변수 "+envPipelines+")")
+	exports := fs.Bool("exports", false, "앱별 내보내기 파일도 함께 저장")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	query := strings.Join(fs.Args(), " ")
+	if *repoDir == "" || strings.TrimSpace(query) == "" {
+		stderrPrintf("사용법: ahtvc autoeq -repo DIR [-out DIR] [-pick N] [-pipeline name] [-pipelines file] [-exports] 검색어")
+		return 2
+	}
+
+	index, err := buildAutoEQIndex(*repoDir)
+	if err != nil {
+		stderrPrintf("AutoEQ 저장소 색인 오류: %v", err)
+		return 1
+	}
+	matches := index.Search(query, 0)
+	if len(matches) == 0 {
+		stderrPrintf("'%s' 와 일치하는 기기가 없습니다 (색인된 기기 %d개)", query, index.Len())
+		return 1
+	}
+
+	if *outDir == "" {
+		for i, device := range matches {
+			if *limit > 0 && i >= *limit {
+				consolePrintf("... 외 %d개", len(matches)-i)
+				break
+			}
+			fmt.Printf("%3d. %s [%s]\n     %s\n", i+1, device.Name, device.Source, device.Path)
+		}
+		return 0
+	}
+
+	var device autoEQDevice
+	switch {
+	case *pick > 0 && *pick <= len(matches):
+		device = matches[*pick-1]
+	case *pick != 0:
+		stderrPrintf("-pick 번호가 올바르지 않습니다 (1~%d): %d", len(matches), *pick)
+		return 2
+	case len(matches) == 1:
+		device = matches[0]
+	default:
+		stderrPrintf("'%s' 와 일치하는 기기가 %d개입니다. 검색어를 더 구체적으로 하거나 -pick 번호를 지정하세요.", query, len(matches))
+		return 1
+	}
+
+	pipeline, err := findPipeline(*pipelinesPath, *pipelineName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, logLang.Err(err))
+		return 1
+	}
+	filename, content, err := index.Read(device)
+	if err != nil {
+		stderrPrintf("기기 파일 읽기 오류 (%s): %v", device.Path, err)
+		return 1
+	}
+	input, err := eq.ParseInput(string(content))
+	if err != nil {
+		stderrPrintf("입력 파일 파싱 오류 (%s): %v", device.Path, err)
+		return 1
+	}
+	results, err := convertSource(convertInput{
+		Source:         input.Curve,
+		Channels:       input.Channels,
+		SourceFilename: filename,
+		SourcePath:     device.Path,
+		Target:         eq.DetectTarget(device.Path, string(content)),
+	}, pipeline, convertOptions{Format: eq.DefaultFormatOptions, Exports: *exports})
+	if err != nil {
+		stderrPrintf("변환 오류: %v", err)
+		return 1
+	}
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		stderrPrintf("출력 폴더 생성 오류: %v", err)
+		return 1
+	}
+	outputs, err := writeConvertResults(*outDir, results)
+	if err != nil {
+		stderrPrintf("저장 오류: %v", err)
+		return 1
+	}
+	consolePrintf("%s [%s] 변환 완료: %s", device.Name, device.Source, strings.Join(outputs, ", "))
+	return 0
+}
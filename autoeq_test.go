@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 임시 AutoEQ 저장소 (results/<출처>/<타겟>/<기기>/...)
+func newTestAutoEQRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	core := readCoreEQ(t)
+	files := map[string][]byte{
+		"results/oratory1990/harman_over-ear_2018/Sennheiser HD 650/Sennheiser HD 650 GraphicEQ.txt":    core,
+		"results/oratory1990/harman_over-ear_2018/Sennheiser HD 650/Sennheiser HD 650 ParametricEQ.txt": []byte("Preamp: -6.1 dB\n"),
+		"results/crinacle/711 in-ear/Moondrop Blessing 2/Moondrop Blessing 2 GraphicEQ.txt":             core,
+		"results/crinacle/711 in-ear/Sennheiser IE 600/Sennheiser IE 600.csv":                           []byte("frequency,raw,equalization\n20,0,1\n1000,0,0\n20000,0,-2\n"),
+		".git/config": []byte("[core]\n"),
+	}
+	for name, content := range files {
+		path := filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestAutoEQIndexSearch(t *testing.T) {
+	index, err := buildAutoEQIndex(newTestAutoEQRepo(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index.Len() != 3 {
+		t.Fatalf("색인된 기기 %d개, want 3", index.Len())
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"hd650", []string{"Sennheiser HD 650"}},
+		{"Sennheiser", []string{"Sennheiser HD 650", "Sennheiser IE 600"}},
+		{"sennheiser crinacle", []string{"Sennheiser IE 600"}},
+		{"blessing2", []string{"Moondrop Blessing 2"}},
+		{"없는 기기", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, device := range index.Search(tt.query, 0) {
+			got = append(got, device.Name)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+	if _, err := index.Device("../../etc/passwd"); err == nil {
+		t.Error("색인에 없는 경로가 허용됨")
+	}
+}
+
+func TestHandleConvertAutoEQDevice(t *testing.T) {
+	index, err := buildAutoEQIndex(newTestAutoEQRepo(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	activeAutoEQ = index
+	t.Cleanup(func() { activeAutoEQ = nil })
+	store := useTempHistory(t)
+
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?q=hd650", nil))
+	devicePath := "oratory1990/harman_over-ear_2018/Sennheiser HD 650/Sennheiser HD 650 GraphicEQ.txt"
+	if !strings.Contains(rec.Body.String(), "Sennheiser HD 650</a>") {
+		t.Fatalf("검색 결과가 없음:\n%s", rec.Body.String())
+	}
+
+	req := newUploadRequest(t, "/", nil, map[string]string{csrfFieldName: testCSRFToken, "device": devicePath}, testCSRFToken)
+	rec = httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Sennheiser HD 650_AHTVC-By_MiFun.txt") || !strings.Contains(body, "Target: Harman\n") {
+		t.Error("저장소 파일 변환 결과가 올바르지 않음")
+	}
+
+	// 기록에서 다시 실행해도 기기 경로로 이름과 타겟을 추정
+	summaries, err := store.Summaries()
+	if err != nil || len(summaries) != 1 {
+		t.Fatalf("변환 기록 = %v (err %v), want 1개", summaries, err)
+	}
+	req = newUploadRequest(t, "/", nil, map[string]string{csrfFieldName: testCSRFToken, "historyID": summaries[0].ID}, testCSRFToken)
+	rec = httptest.NewRecorder()
+	handleConvert(rec, req)
+	body = rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, "Sennheiser HD 650_AHTVC-By_MiFun.txt") || !strings.Contains(body, "Target: Harman\n") {
+		t.Errorf("다시 실행 결과가 원래 변환과 다름 (status %d)", rec.Code)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"ahtvc/eq"
+)
+
+// 비교 결과와 오버레이 그래프
+type compareView struct {
+	eq.Comparison
+	Plot template.HTML `json:"-"`
+}
+
+// 비교 페이지 템플릿 (index 템플릿의 스타일 재사용)
+var compareTemplate = template.Must(template.Must(indexTemplate.Clone()).Parse(`
+<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+    <meta charset="UTF-8">
+    <title>AHTVC - {{t "EQ 비교"}}</title>
+    {{template "style"}}
+</head>
+<body>
+    {{template "langs" .}}
+    <h1>{{t "EQ 파일 비교"}}</h1>
+    <p>{{t "두 GraphicEQ 파일(예: 이전 AHTVC 결과와 새 결과, Result 1과 Result 2)을 공통 로그 격자에서 비교합니다."}} <a href="/">{{t "변환 페이지로"}}</a></p>
+    <form method="POST" enctype="multipart/form-data">
+        <input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+        <label for="fileA">{{t "파일 A (.txt/.csv):"}}</label>
+        <input type="file" id="fileA" name="fileA" accept=".txt,.csv" required>
+        <label for="fileB">{{t "파일 B (.txt/.csv):"}}</label>
+        <input type="file" id="fileB" name="fileB" accept=".txt,.csv" required>
+        <br><br>
+        <input type="submit" value="{{t "비교하기"}}">
+    </form>
+    {{with .Compare}}
+    <div class="result-container">
+        <div class="result-box">
+            <div class="filename">A: {{.NameA}} / B: {{.NameB}}</div>
+            {{.Plot}}
+            <table class="metrics">
+                <tr><td>{{t "최대 차이 (B - A)"}}</td><td>{{printf "%.2f" .MaxDelta}} dB @ {{printf "%.0f" .MaxDeltaFreq}} Hz</td></tr>
+                <tr><td>{{t "RMS 차이"}}</td><td>{{printf "%.2f" .RMSDelta}} dB</td></tr>
+                <tr><td>{{t "평균 차이"}}</td><td>{{printf "%.2f" .MeanDelta}} dB</td></tr>
+            </table>
+            <table class="metrics">
+                <tr><th>{{t "주파수 (Hz)"}}</th><th>A (dB)</th><th>B (dB)</th><th>B - A (dB)</th></tr>
+                {{range .Rows}}<tr><td>{{printf "%.0f" .Freq}}</td><td>{{printf "%.1f" .A}}</td><td>{{printf "%.1f" .B}}</td><td>{{printf "%+.2f" .Delta}}</td></tr>
+                {{end}}
+            </table>
+        </div>
+    </div>
+    {{end}}
+    {{if .Error}} <div class="error"> <strong>{{t "오류:"}}</strong> <pre>{{.Error}}</pre> </div> {{end}}
+</body>
+</html>
+`))
+
+// 언어별 비교 페이지 템플릿
+var compareTemplates = localizeTemplate(compareTemplate)
+
+// 비교 결과와 그래프 생성
+func compareWithPlot(nameA string, a eq.Curve, nameB string, b eq.Curve, pointsPerOctave int) (compareView, error) {
+	comparison, err := eq.Compare(nameA, a, nameB, b, pointsPerOctave)
+	if err != nil {
+		return compareView{}, err
+	}
+	seriesA := plotSeries{Name: "A: " + nameA}
+	seriesB := plotSeries{Name: "B: " + nameB}
+	seriesDelta := plotSeries{Name: "B - A"}
+	for _, row := range comparison.Rows {
+		seriesA.Freqs, seriesA.Gains = append(seriesA.Freqs, row.Freq), append(seriesA.Gains, row.A)
+		seriesB.Freqs, seriesB.Gains = append(seriesB.Freqs, row.Freq), append(seriesB.Gains, row.B)
+		seriesDelta.Freqs, seriesDelta.Gains = append(seriesDelta.Freqs, row.Freq), append(seriesDelta.Gains, row.Delta)
+	}
+	return compareView{Comparison: comparison, Plot: renderOverlaySVG([]plotSeries{seriesA, seriesB, seriesDelta})}, nil
+}
+
+// 비교 웹 요청 처리 핸들러
+func handleCompare(w http.ResponseWriter, r *http.Request) {
+	lang := negotiateLang(w, r)
+	resultData := map[string]interface{}{}
+	resultData["Lang"] = lang
+	resultData["CSRFToken"] = ensureCSRFToken(w, r)
+
+	if r.Method == http.MethodPost {
+		if status, err := prepareUpload(w, r); err != nil {
+			resultData["Error"] = lang.T("업로드 거부: %v", err)
+			writeCompareResponse(w, r, status, resultData)
+			return
+		}
+		var names [2]string
+		var curves [2]eq.Curve
+		for i, field := range []string{"fileA", "fileB"} {
+			file, handler, err := r.FormFile(field)
+			if err != nil {
+				resultData["Error"] = lang.T("파일 업로드 오류 (%s): %v", field, err)
+				writeCompareResponse(w, r, http.StatusBadRequest, resultData)
+				return
+			}
+			content, err := readTextUpload(file)
+			file.Close()
+			if err != nil {
+				resultData["Error"] = lang.T("파일 읽기 오류 (%s): %v", handler.Filename, err)
+				writeCompareResponse(w, r, uploadErrorStatus(err), resultData)
+				return
+			}
+			input, err := eq.ParseInput(string(content))
+			if err != nil {
+				resultData["Error"] = lang.T("입력 파일 파싱 오류 (%s): %v", handler.Filename, err)
+				writeCompareResponse(w, r, http.StatusBadRequest, resultData)
+				return
+			}
+			curves[i], names[i] = input.Curve, handler.Filename
+		}
+
+		comparison, err := compareWithPlot(names[0], curves[0], names[1], curves[1], eq.DefaultPointsPerOctave)
+		if err != nil {
+			resultData["Error"] = lang.Err(err)
+			writeCompareResponse(w, r, http.StatusBadRequest, resultData)
+			return
+		}
+		resultData["Compare"] = comparison
+	}
+
+	writeCompareResponse(w, r, http.StatusOK, resultData)
+}
+
+// 비교 결과 응답 (JSON 요청이면 writeResponse와 동일한 형식)
+func writeCompareResponse(w http.ResponseWriter, r *http.Request, status int, resultData map[string]interface{}) {
+	if wantsJSON(r) {
+		writeResponse(w, r, status, resultData)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := templateFor(compareTemplates, resultData).Execute(w, resultData); err != nil {
+		logPrintf("비교 템플릿 실행 오류: %v", err)
+	}
+}
+
+// CLI 비교 모드: ahtvc compare [-svg plot.svg] [-ppo 12] A.txt B.txt
+func runCompareCLI(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	svgPath := fs.String("svg", "", "오버레이 그래프를 저장할 SVG 파일 경로")
+	pointsPerOctave := fs.Int("ppo", eq.DefaultPointsPerOctave, "비교 격자의 옥타브당 포인트 수")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 || *pointsPerOctave <= 0 {
+		if *pointsPerOctave <= 0 {
+			stderrPrintf("옥타브당 포인트 수가 올바르지 않습니다: %d", *pointsPerOctave)
+		}
+		stderrPrintf("사용법: ahtvc compare [-svg plot.svg] [-ppo 12] A.txt B.txt")
+		return 2
+	}
+
+	var curves [2]eq.Curve
+	for i, path := range fs.Args() {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			stderrPrintf("파일 읽기 오류: %v", err)
+			return 1
+		}
+		input, err := eq.ParseInput(string(content))
+		if err != nil {
+			stderrPrintf("입력 파일 파싱 오류 (%s): %v", path, err)
+			return 1
+		}
+		curves[i] = input.Curve
+	}
+
+	nameA, nameB := filepath.Base(fs.Arg(0)), filepath.Base(fs.Arg(1))
+	comparison, err := compareWithPlot(nameA, curves[0], nameB, curves[1], *pointsPerOctave)
+	if err != nil {
+		stderrPrintf("비교 오류: %v", err)
+		return 1
+	}
+
+	fmt.Printf("A: %s\nB: %s\n\n", nameA, nameB)
+	fmt.Printf("%10s %8s %8s %8s\n", "Freq(Hz)", "A(dB)", "B(dB)", "B-A(dB)")
+	for _, row := range comparison.Rows {
+		fmt.Printf("%10.0f %8.1f %8.1f %+8.2f\n", row.Freq, row.A, row.B, row.Delta)
+	}
+	fmt.Println()
+	consolePrintf("최대 차이: %+.2f dB @ %.0f Hz", comparison.MaxDelta, comparison.MaxDeltaFreq)
+	consolePrintf("RMS 차이: %.2f dB", comparison.RMSDelta)
+	consolePrintf("평균 차이: %+.2f dB", comparison.MeanDelta)
+
+	if *svgPath != "" {
+		if err := os.WriteFile(*svgPath, []byte(comparison.Plot), 0644); err != nil {
+			stderrPrintf("SVG 저장 오류: %v", err)
+			return 1
+		}
+		consolePrintf("그래프 저장됨: %s", *svgPath)
+	}
+	return 0
+}
@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+func TestRunCompareCLIRejectsBadPPO(t *testing.T) {
+	for _, ppo := range []string{"0", "-3"} {
+		if code := runCompareCLI([]string{"-ppo", ppo, "AHTVC_Core-By_MiFun.txt", "AHTVC_Core-By_MiFun.txt"}); code != 2 {
+			t.Errorf("-ppo %s: 종료 코드 = %d, want 2", ppo, code)
+		}
+	}
+}
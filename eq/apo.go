@@ -0,0 +1,384 @@
+package eq
+
+import (
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Equalizer APO 설정 파싱 상수
+const (
+	apoSampleRate            = 48000    // Filter 라인 응답 계산용 샘플레이트 (Hz)
+	apoFilterPointsPerOctave = 24       // Filter 라인 응답을 계산하는 로그 격자 해상도
+	maxIncludeDepth          = 8        // Include 중첩 한도
+	maxIncludeBytes          = 16 << 20 // Include 를 펼친 설정의 최대 크기 (ZIP 폭탄 방지)
+)
+
+// ChannelCurve 는 Equalizer APO 설정에서 한 채널 (또는 모든 채널) 에 적용되는 EQ 입니다.
+type ChannelCurve struct {
+	Channel string  // 채널 이름 (예: L, R). 비어 있으면 모든 채널
+	Curve   Curve   // GraphicEQ/Filter 라인을 모두 합산한 곡선
+	Preamp  float64 // Preamp 라인 합계 (dB, 곡선에는 포함하지 않음)
+}
+
+// 채널 선택에 적용되는 필터 하나 (channels 가 nil 이면 모든 채널)
+type apoEvent struct {
+	channels []string
+	curve    Curve
+	preamp   float64
+}
+
+// ParseAPO 는 Equalizer APO 설정을 채널별 곡선으로 파싱합니다.
+// GraphicEQ/Filter 라인은 모두 합산하고, Channel 라인으로 고른 채널에만 이후 필터를 적용하며, Preamp 라인은 따로 더합니다.
+// 채널마다 결과가 같으면 채널 이름이 없는 곡선 하나를 반환합니다. Include 라인은 ExpandIncludes 로 먼저 펼쳐야 합니다.
+func ParseAPO(content string) ([]ChannelCurve, error) {
+	var events []apoEvent
+	var selected []string // nil 이면 모든 채널
+	filterFound := false
+	ignored := map[string]bool{}
+
+	for lineNum, line := range splitLines(content) {
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		command, args, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		command, args = strings.ToLower(strings.TrimSpace(command)), strings.TrimSpace(args)
+		switch {
+		case command == "graphiceq":
+			filterFound = true
+			curve, err := parseGraphicEQLine(lineNum+1, line, args)
+			if err != nil {
+				return nil, err
+			}
+			if len(curve) > 0 {
+				events = append(events, apoEvent{channels: selected, curve: curve})
+			}
+		case command == "filter" || strings.HasPrefix(command, "filter "):
+			filterFound = true
+			if filter, on := parseFilterLine(lineNum+1, args); on {
+				events = append(events, apoEvent{channels: selected, curve: filterCurve(filter)})
+			}
+		case command == "preamp":
+			value := args
+			if hasSuffixFold(value, "dB") {
+				value = strings.TrimSpace(value[:len(value)-2])
+			}
+			gain, err := strconv.ParseFloat(value, 64)
+			if err != nil || isInvalid(gain) {
+				logf("경고: Line %d, 잘못된 Preamp 값 무시: '%s'\n", lineNum+1, args)
+				continue
+			}
+			events = append(events, apoEvent{channels: selected, preamp: gain})
+		case command == "channel":
+			selected = parseChannels(lineNum+1, args)
+		case command == "include":
+			return nil, errorf("line %d: Include 파일을 불러올 수 없음 ('%s'): 설정 폴더를 ZIP 으로 업로드하세요", lineNum+1, args)
+		default:
+			if !ignored[command] {
+				ignored[command] = true
+				logf("경고: Line %d, 지원하지 않는 Equalizer APO 명령 무시: '%s'\n", lineNum+1, command)
+			}
+		}
+	}
+
+	if !filterFound {
+		return nil, errorf("'GraphicEQ:' 또는 'Filter:' 라인을 찾을 수 없음 (파일 형식을 확인하세요)")
+	}
+	channels := mergeChannels(events)
+	for _, ch := range channels {
+		if len(ch.Curve) == 0 {
+			return nil, errorf("파싱된 유효한 EQ 데이터 포인트가 없음")
+		}
+	}
+	return channels, nil
+}
+
+// GraphicEQ 라인 파싱 (잘못된 포인트는 경고 후 무시)
+func parseGraphicEQLine(lineNum int, line, pointsStr string) (Curve, error) {
+	var data []Point
+	points := strings.Split(pointsStr, ";")
+	for pointIdx, point := range points {
+		point = strings.TrimSpace(point)
+		if point == "" {
+			if pointIdx != len(points)-1 {
+				logf("경고: Line %d, 비어있는 EQ 포인트 발견 (인덱스 %d).\n", lineNum, pointIdx)
+			}
+			continue
+		}
+		parts := strings.Fields(point)
+		if len(parts) != 2 {
+			logf("경고: Line %d, 잘못된 포인트 형식 무시 (항목 %d개): '%s'\n", lineNum, len(parts), point)
+			continue
+		}
+		freq, errF := strconv.ParseFloat(parts[0], 64)
+		gain, errG := strconv.ParseFloat(parts[1], 64)
+		if errF != nil || errG != nil {
+			logf("경고: Line %d, 숫자 변환 오류 무시 ('%s'): %v, %v\n", lineNum, point, errF, errG)
+			continue
+		}
+		if isInvalid(gain) {
+			logf("경고: Line %d, 잘못된 게인 값 (NaN or Inf) 무시 at freq %s\n", lineNum, FormatFreq(freq))
+			continue
+		}
+		if isInvalid(freq) || freq <= 0 || freq > maxFreq {
+			logf("경고: Line %d, 비정상적인 주파수 값 무시: %s\n", lineNum, parts[0])
+			continue
+		}
+		data = append(data, Point{Freq: freq, Gain: gain})
+	}
+	if len(data) == 0 && strings.TrimSpace(points[0]) != "" {
+		return nil, errorf("line %d: GraphicEQ 라인에서 유효한 포인트를 찾을 수 없음: '%s'", lineNum, line)
+	}
+	return CurveFromPoints(data), nil
+}
+
+// Filter 라인 파싱 (예: "ON PK Fc 100 Hz Gain -3 dB Q 1.41"). 꺼져 있거나 지원하지 않는 필터면 on = false
+func parseFilterLine(lineNum int, args string) (filter PEQFilter, on bool) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 || !strings.EqualFold(fields[0], "ON") {
+		return filter, false
+	}
+	switch strings.ToUpper(fields[1]) {
+	case "PK", "PEQ":
+		filter.Type = FilterPeaking
+	case "LSC", "LS":
+		filter.Type = FilterLowShelf
+	case "HSC", "HS":
+		filter.Type = FilterHighShelf
+	default:
+		logf("경고: Line %d, 지원하지 않는 필터 종류 무시: '%s'\n", lineNum, fields[1])
+		return filter, false
+	}
+	filter.Q = 0.707 // Q 가 없는 셸빙 필터 기본값
+	for i := 2; i+1 < len(fields); i++ {
+		value, err := strconv.ParseFloat(fields[i+1], 64)
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(fields[i]) {
+		case "fc":
+			filter.Freq = value
+		case "gain":
+			filter.Gain = value
+		case "q":
+			filter.Q = value
+		}
+	}
+	if isInvalid(filter.Gain) || isInvalid(filter.Q) || filter.Q <= 0 || !(filter.Freq > 0 && filter.Freq < apoSampleRate/2) {
+		logf("경고: Line %d, 잘못된 필터 값 무시: '%s'\n", lineNum, args)
+		return filter, false
+	}
+	return filter, true
+}
+
+// 필터 응답을 로그 격자 곡선으로 계산
+func filterCurve(filter PEQFilter) Curve {
+	grid := LogGrid(20, 20000, apoFilterPointsPerOctave)
+	biquad := filter.Biquad(apoSampleRate)
+	curve := make(Curve, len(grid))
+	for i, freq := range grid {
+		curve[i] = Point{Freq: freq, Gain: biquad.ResponseDB(freq, apoSampleRate)}
+	}
+	return curve
+}
+
+// Channel 라인의 채널 목록 ("all" 이면 nil)
+func parseChannels(lineNum int, args string) []string {
+	var channels []string
+	for _, name := range strings.Fields(args) {
+		if strings.EqualFold(name, "all") {
+			return nil
+		}
+		if deviceKey(name) != strings.ToLower(name) {
+			logf("경고: Line %d, 잘못된 채널 이름 무시: '%s'\n", lineNum, name)
+			continue
+		}
+		channels = append(channels, strings.ToUpper(name))
+	}
+	return channels
+}
+
+// 기본 스테레오 채널 (Channel 라인에 이름이 나오지 않아도 항상 출력)
+var stereoChannels = []string{"L", "R"}
+
+// 채널별로 필터를 합산 (이름이 나온 채널이 없으면 모든 채널 곡선 하나)
+// 일부 채널만 이름이 나오면 나머지 스테레오 채널도 전체 채널 필터(Preamp 등)만으로 출력합니다.
+func mergeChannels(events []apoEvent) []ChannelCurve {
+	var named []string
+	seen := map[string]bool{}
+	for _, e := range events {
+		for _, name := range e.channels {
+			if !seen[name] {
+				seen[name] = true
+				named = append(named, name)
+			}
+		}
+	}
+	names := []string{""}
+	if len(named) > 0 {
+		names = named
+		if !seen[stereoChannels[0]] || !seen[stereoChannels[1]] {
+			names = append([]string(nil), stereoChannels...)
+			for _, name := range named {
+				if !containsString(stereoChannels, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	channels := make([]ChannelCurve, len(names))
+	for i, name := range names {
+		var curves []Curve
+		channels[i].Channel = name
+		for _, e := range events {
+			if e.channels != nil && !containsString(e.channels, name) {
+				continue
+			}
+			channels[i].Preamp += e.preamp
+			if len(e.curve) > 0 {
+				curves = append(curves, e.curve)
+			}
+		}
+		channels[i].Curve = sumCurves(curves)
+		if len(channels[i].Curve) == 0 {
+			// 필터가 없는 채널은 다른 채널과 같은 주파수의 0 dB 곡선
+			channels[i].Curve = flatCurve(events)
+		}
+	}
+
+	// 여러 채널이 모두 같으면 하나로 합침
+	if len(channels) == 1 {
+		return channels
+	}
+	for _, ch := range channels[1:] {
+		if ch.Preamp != channels[0].Preamp || !equalCurves(ch.Curve, channels[0].Curve) {
+			return channels
+		}
+	}
+	return []ChannelCurve{{Curve: channels[0].Curve, Preamp: channels[0].Preamp}}
+}
+
+// 모든 필터 곡선의 주파수를 합친 0 dB 곡선 (필터가 하나도 없으면 nil)
+func flatCurve(events []apoEvent) Curve {
+	var points []Point
+	for _, e := range events {
+		points = append(points, e.curve...)
+	}
+	flat := CurveFromPoints(points)
+	for i := range flat {
+		flat[i].Gain = 0
+	}
+	return flat
+}
+
+// 곡선 합산 (모든 곡선의 주파수를 합친 격자에서 로그-선형 보간, 곡선이 하나면 그대로)
+func sumCurves(curves []Curve) Curve {
+	switch len(curves) {
+	case 0:
+		return nil
+	case 1:
+		return curves[0].Clone()
+	}
+	var points []Point
+	for _, c := range curves {
+		points = append(points, c...)
+	}
+	// Equalizer APO 도 GraphicEQ 포인트 사이를 로그-선형 보간하므로 같은 방식으로 합산
+	grid := CurveFromPoints(points)
+	for i := range grid {
+		grid[i].Gain = 0
+	}
+	for _, c := range curves {
+		it, err := NewInterpolator(c, InterpLogLinear)
+		if err != nil {
+			logf("경고: 곡선 합산 중 보간 실패 (%v). 해당 곡선을 건너뜁니다.\n", err)
+			continue
+		}
+		for i := range grid {
+			grid[i].Gain += it.At(grid[i].Freq)
+		}
+	}
+	return grid
+}
+
+func equalCurves(a, b Curve) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandIncludes 는 fsys 의 name 설정 파일에서 Include 라인을 해당 파일 내용으로 펼칩니다.
+// Include 경로는 포함하는 파일의 폴더 기준이며 (\ 구분자 허용), fsys 밖을 가리키거나 순환하면 오류입니다.
+func ExpandIncludes(fsys fs.FS, name string) (string, error) {
+	var sb strings.Builder
+	if err := expandIncludes(fsys, name, &sb, nil); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func expandIncludes(fsys fs.FS, name string, sb *strings.Builder, stack []string) error {
+	if len(stack) >= maxIncludeDepth {
+		return errorf("Include 중첩이 너무 깊습니다 (%d단계 초과): '%s'", maxIncludeDepth, name)
+	}
+	if containsString(stack, name) {
+		return errorf("순환 Include: '%s'", name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return errorf("Include 파일을 열 수 없음 ('%s'): %w", name, err)
+	}
+	content, err := io.ReadAll(io.LimitReader(f, int64(maxIncludeBytes-sb.Len()+1)))
+	f.Close()
+	if err != nil {
+		return errorf("Include 파일 읽기 오류 ('%s'): %w", name, err)
+	}
+	if sb.Len()+len(content) > maxIncludeBytes {
+		return errorf("Include 를 펼친 설정이 너무 큽니다 (%dMB 초과)", maxIncludeBytes>>20)
+	}
+
+	stack = append(stack, name)
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		command, args, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(command), "include") {
+			sb.WriteString(line)
+			continue
+		}
+		target := strings.ReplaceAll(strings.TrimSpace(args), `\`, "/")
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(name), target)
+		}
+		if !fs.ValidPath(target) {
+			return errorf("Include 경로가 설정 폴더 밖을 가리킵니다: '%s'", args)
+		}
+		sb.WriteString("# Include: " + target + "\n")
+		if err := expandIncludes(fsys, target, sb, stack); err != nil {
+			return err
+		}
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		sb.WriteString("\n")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package eq
+
+import (
+	"math"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseAPOChannels(t *testing.T) {
+	silenceLogs(t)
+	config := `Preamp: -6 dB
+GraphicEQ: 20 1; 1000 0
+Channel: L
+GraphicEQ: 20 2; 1000 2
+Preamp: -1 dB
+Channel: R
+GraphicEQ: 100 -1
+Channel: all
+GraphicEQ: 20 1; 1000 1
+Device: Speakers
+`
+	channels, err := ParseAPO(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 || channels[0].Channel != "L" || channels[1].Channel != "R" {
+		t.Fatalf("채널 = %+v, want L, R", channels)
+	}
+	want := map[string]struct {
+		curve  Curve
+		preamp float64
+	}{
+		"L": {Curve{{20, 4}, {1000, 3}}, -7},
+		"R": {Curve{{20, 1}, {100, linearAt(t, Curve{{20, 1}, {1000, 0}}, 100)}, {1000, 0}}, -6}, // 단일 포인트 -1 dB 는 전 대역에 적용
+	}
+	for _, ch := range channels {
+		w := want[ch.Channel]
+		if ch.Preamp != w.preamp {
+			t.Errorf("%s Preamp = %g, want %g", ch.Channel, ch.Preamp, w.preamp)
+		}
+		if len(ch.Curve) != len(w.curve) {
+			t.Errorf("%s 곡선 = %v, want %v", ch.Channel, ch.Curve, w.curve)
+			continue
+		}
+		for i := range ch.Curve {
+			if ch.Curve[i].Freq != w.curve[i].Freq || math.Abs(ch.Curve[i].Gain-w.curve[i].Gain) > 1e-9 {
+				t.Errorf("%s 곡선 = %v, want %v", ch.Channel, ch.Curve, w.curve)
+				break
+			}
+		}
+	}
+
+	// 채널마다 같으면 하나로 합침
+	channels, err = ParseAPO("Channel: L R\nGraphicEQ: 20 1; 1000 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 || channels[0].Channel != "" {
+		t.Errorf("같은 채널이 합쳐지지 않음: %+v", channels)
+	}
+
+	// Channel 라인에 L 만 있어도 R 은 공통 Preamp 와 필터로 출력
+	channels, err = ParseAPO("Preamp: -2 dB\nGraphicEQ: 20 1; 1000 1\nChannel: L\nGraphicEQ: 20 2; 1000 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 || channels[0].Channel != "L" || channels[1].Channel != "R" {
+		t.Fatalf("채널 = %+v, want L, R", channels)
+	}
+	if channels[1].Preamp != -2 || linearAt(t, channels[1].Curve, 100) != 1 || linearAt(t, channels[0].Curve, 100) != 3 {
+		t.Errorf("한 채널만 지정한 설정의 채널 곡선이 올바르지 않음: %+v", channels)
+	}
+
+	// 필터가 없는 채널은 0 dB
+	channels, err = ParseAPO("Channel: R\nGraphicEQ: 20 -1; 1000 -1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 2 || channels[0].Channel != "L" || linearAt(t, channels[0].Curve, 100) != 0 {
+		t.Errorf("필터가 없는 L 채널이 0 dB 가 아님: %+v", channels)
+	}
+
+	input, err := ParseInput(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.Format != InputGraphicEQ || len(input.Channels) != 2 || input.Description() != "GraphicEQ (L, R)" {
+		t.Errorf("ParseInput() = %+v (%s)", input, input.Description())
+	}
+}
+
+func TestParseAPOFilters(t *testing.T) {
+	silenceLogs(t)
+	channels, err := ParseAPO(`Preamp: -5.2 dB
+Filter 1: ON LSC Fc 105 Hz Gain 5.0 dB Q 0.70
+Filter 2: ON PK Fc 1000 Hz Gain -3.0 dB Q 1.41
+Filter 3: OFF PK Fc 3000 Hz Gain 10 dB Q 1
+Filter 4: ON HSC Fc 10000 Hz Gain -2.0 dB Q 0.70
+Filter 5: ON LP Fc 18000 Hz
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve := channels[0].Curve
+	if channels[0].Preamp != -5.2 {
+		t.Errorf("Preamp = %g, want -5.2", channels[0].Preamp)
+	}
+	tests := []struct {
+		freq, want, tolerance float64
+	}{
+		{20, 5, 0.1},     // 로우 셸프
+		{1000, -3, 0.05}, // 피킹 중심
+		{3000, 0, 0.3},   // 꺼진 필터는 무시
+		{20000, -2, 0.1}, // 하이 셸프
+	}
+	for _, tt := range tests {
+		if got := linearAt(t, curve, tt.freq); math.Abs(got-tt.want) > tt.tolerance {
+			t.Errorf("%g Hz = %.3f dB, want %.1f ± %.2f", tt.freq, got, tt.want, tt.tolerance)
+		}
+	}
+	if DetectFormat("Preamp: -5 dB\nFilter 1: ON PK Fc 100 Hz Gain 1 dB Q 1\n") != InputGraphicEQ {
+		t.Error("ParametricEQ 파일이 Equalizer APO 설정으로 감지되지 않음")
+	}
+}
+
+func TestExpandIncludes(t *testing.T) {
+	silenceLogs(t)
+	fsys := fstest.MapFS{
+		"config.txt":         {Data: []byte("Preamp: -3 dB\nInclude: eq\\left.txt\nChannel: R\nInclude: eq/right.txt")},
+		"eq/left.txt":        {Data: []byte("Channel: L\nGraphicEQ: 20 1; 1000 1\nInclude: common/bass.txt\n")},
+		"eq/right.txt":       {Data: []byte("GraphicEQ: 20 2; 1000 2")},
+		"eq/common/bass.txt": {Data: []byte("GraphicEQ: 20 3; 100 0")},
+		"loop.txt":           {Data: []byte("Include: loop.txt")},
+		"escape.txt":         {Data: []byte("Include: ../secret.txt")},
+		"missing.txt":        {Data: []byte("Include: nowhere.txt")},
+	}
+	content, err := ExpandIncludes(fsys, "config.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	channels, err := ParseAPO(content)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, content)
+	}
+	if len(channels) != 2 || channels[0].Channel != "L" || channels[1].Channel != "R" {
+		t.Fatalf("채널 = %+v", channels)
+	}
+	if got := linearAt(t, channels[0].Curve, 20); got != 4 {
+		t.Errorf("L 20Hz = %g, want 4 (중첩 Include 합산)", got)
+	}
+	if got := linearAt(t, channels[1].Curve, 20); got != 2 {
+		t.Errorf("R 20Hz = %g, want 2", got)
+	}
+
+	for _, name := range []string{"loop.txt", "escape.txt", "missing.txt"} {
+		if _, err := ExpandIncludes(fsys, name); err == nil {
+			t.Errorf("ExpandIncludes(%s): 오류가 없음", name)
+		}
+	}
+	if _, err := ParseAPO("Include: other.txt\nGraphicEQ: 20 1"); err == nil {
+		t.Error("펼치지 않은 Include 가 조용히 무시됨")
+	}
+}
@@ -0,0 +1,84 @@
+package eq
+
+import (
+	"errors"
+	"math"
+)
+
+// DefaultPointsPerOctave 는 비교용 로그 격자의 기본 해상도입니다 (옥타브당 포인트 수).
+const DefaultPointsPerOctave = 12
+
+// CompareRow 는 비교 격자의 한 포인트입니다.
+type CompareRow struct {
+	Freq  float64 `json:"freq"`
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"`
+}
+
+// Comparison 은 두 EQ 곡선의 비교 결과입니다.
+type Comparison struct {
+	NameA        string       `json:"nameA"`
+	NameB        string       `json:"nameB"`
+	Rows         []CompareRow `json:"rows"`
+	MaxDelta     float64      `json:"maxDelta"`
+	MaxDeltaFreq float64      `json:"maxDeltaFreq"`
+	RMSDelta     float64      `json:"rmsDelta"`
+	MeanDelta    float64      `json:"meanDelta"`
+}
+
+// LogGrid 는 startFreq ~ endFreq 사이의 로그 격자를 만듭니다 (옥타브당 pointsPerOctave 개).
+func LogGrid(startFreq, endFreq float64, pointsPerOctave int) []float64 {
+	var grid []float64
+	if startFreq <= 0 || endFreq < startFreq || pointsPerOctave <= 0 {
+		return grid
+	}
+	steps := int(math.Floor(math.Log2(endFreq/startFreq) * float64(pointsPerOctave)))
+	for i := 0; i <= steps; i++ {
+		grid = append(grid, startFreq*math.Pow(2, float64(i)/float64(pointsPerOctave)))
+	}
+	if last := grid[len(grid)-1]; endFreq-last > 1e-6 {
+		grid = append(grid, endFreq)
+	}
+	return grid
+}
+
+// Compare 는 두 곡선을 공통 로그 격자에 맞춰 비교합니다 (겹치는 주파수 범위만 사용).
+func Compare(nameA string, a Curve, nameB string, b Curve, pointsPerOctave int) (Comparison, error) {
+	result := Comparison{NameA: nameA, NameB: nameB}
+	pointsA, pointsB := a.Points(), b.Points()
+	if len(pointsA) == 0 || len(pointsB) == 0 {
+		return result, errorf("비교할 EQ 데이터가 없습니다")
+	}
+	low := math.Max(pointsA[0].Freq, pointsB[0].Freq)
+	high := math.Min(pointsA[len(pointsA)-1].Freq, pointsB[len(pointsB)-1].Freq)
+	if low >= high {
+		return result, errorf("두 파일의 주파수 범위가 겹치지 않습니다 (A: %g-%g Hz, B: %g-%g Hz)",
+			pointsA[0].Freq, pointsA[len(pointsA)-1].Freq, pointsB[0].Freq, pointsB[len(pointsB)-1].Freq)
+	}
+
+	grid := LogGrid(low, high, pointsPerOctave)
+	gridA, errA := Resample(a, grid, InterpLogLinear)
+	gridB, errB := Resample(b, grid, InterpLogLinear)
+	if err := errors.Join(errA, errB); err != nil {
+		return result, err
+	}
+
+	var deltas []float64
+	for i, freq := range grid {
+		gainA, gainB := gridA[i].Gain, gridB[i].Gain
+		delta := gainB - gainA
+		result.Rows = append(result.Rows, CompareRow{Freq: freq, A: gainA, B: gainB, Delta: delta})
+		deltas = append(deltas, delta)
+		if math.Abs(delta) > math.Abs(result.MaxDelta) {
+			result.MaxDelta = delta
+			result.MaxDeltaFreq = freq
+		}
+	}
+	result.RMSDelta = RMS(deltas)
+	for _, d := range deltas {
+		result.MeanDelta += d
+	}
+	result.MeanDelta /= float64(len(deltas))
+	return result, nil
+}
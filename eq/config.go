@@ -0,0 +1,261 @@
+package eq
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+)
+
+// 설정 파일의 단계 종류
+const (
+	StageTarget        = "target"
+	StageSmooth        = "smooth"
+	StageLayer         = "layer"
+	StageNoPreamp      = "noPreamp"
+	StageLoudness      = "loudness"
+	TargetHarmanToVDSF = "harmanToVdsf" // 내장 Harman -> VDSF 차이 곡선
+)
+
+// Config 는 파이프라인 정의 파일입니다.
+type Config struct {
+	Pipelines []PipelineConfig `json:"pipelines"`
+}
+
+// PipelineConfig 는 이름이 있는 파이프라인(여러 출력)입니다.
+type PipelineConfig struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Outputs     []OutputConfig `json:"outputs"`
+}
+
+// OutputConfig 는 하나의 출력 파일을 만드는 단계 목록입니다.
+type OutputConfig struct {
+	Name   string        `json:"name"`
+	Suffix string        `json:"suffix"` // 출력 파일 이름 접미사 (예: _AHTVC-By_MiFun)
+	Stages []StageConfig `json:"stages"`
+}
+
+// StageConfig 는 단계 하나의 종류와 파라미터입니다.
+//
+//	{"type": "target", "target": "harmanToVdsf"}         내장 타겟 차이 곡선
+//	{"type": "target", "points": [{"freq": 20, "gain": -0.7}, ...]}
+//	{"type": "smooth", "window": 5, "startFreq": 8000}
+//	{"type": "layer", "name": "x2", "points": [...], "interpolation": "pchip"}
+//	{"type": "loudness", "refPhon": 80, "listenPhon": 60}   ISO 226 등청감 보정
+//	{"type": "noPreamp"}
+//	{"type": "noPreamp", "mode": "interpolated", "headroom": 1}
+type StageConfig struct {
+	Type      string  `json:"type"`
+	Target    string  `json:"target,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Window    int     `json:"window,omitempty"`
+	StartFreq float64 `json:"startFreq,omitempty"`
+	Points    []Point `json:"points,omitempty"`
+	// target/layer 단계의 보간 방식 (linear, pchip, cubic)
+	// target 단계에 지정하면 차이 곡선을 소스 주파수로 보간해 더합니다.
+	Interpolation string `json:"interpolation,omitempty"`
+	// noPreamp 단계의 최대 게인 계산 방식 (sampled, interpolated) 과 추가 여유 (dB)
+	Mode     string  `json:"mode,omitempty"`
+	Headroom float64 `json:"headroom,omitempty"`
+	// loudness 단계의 기준 음량과 청취 음량 (phon, 20~90)
+	RefPhon    float64 `json:"refPhon,omitempty"`
+	ListenPhon float64 `json:"listenPhon,omitempty"`
+}
+
+// DefaultConfig 는 내장 파이프라인 정의입니다 (기존 결과 1, 결과 2).
+func DefaultConfig() Config {
+	smooth := StageConfig{Type: StageSmooth, Window: MovingAverageWindow, StartFreq: SmoothStartFreq}
+	return Config{Pipelines: []PipelineConfig{{
+		Name:        "vdsf",
+		Description: "Harman -> VDSF (기본)",
+		Outputs: []OutputConfig{
+			{
+				Name:   "Result 1",
+				Suffix: "_AHTVC-By_MiFun",
+				Stages: []StageConfig{{Type: StageTarget, Target: TargetHarmanToVDSF}, smooth, {Type: StageNoPreamp}},
+			},
+			{
+				Name:   "Result 2",
+				Suffix: "_AHTVCLr2-By_MiFun",
+				Stages: []StageConfig{{Type: StageTarget, Target: TargetHarmanToVDSF}, smooth, {Type: StageLayer, Name: "x2", Points: X2Layer}, smooth, {Type: StageNoPreamp}},
+			},
+		},
+	}}}
+}
+
+// LoadConfigFile 은 JSON 파이프라인 정의 파일을 읽고 검증합니다.
+func LoadConfigFile(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	return LoadConfig(f)
+}
+
+// LoadConfig 는 JSON 파이프라인 정의를 읽고 검증합니다.
+func LoadConfig(r io.Reader) (Config, error) {
+	var cfg Config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, errorf("파이프라인 정의 파싱 오류: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate 는 모든 파이프라인이 만들어질 수 있는지 확인합니다.
+func (cfg Config) Validate() error {
+	if len(cfg.Pipelines) == 0 {
+		return errorf("정의된 파이프라인이 없습니다")
+	}
+	names := make(map[string]bool)
+	for _, pc := range cfg.Pipelines {
+		if pc.Name == "" {
+			return errorf("파이프라인 이름이 비어 있습니다")
+		}
+		if names[pc.Name] {
+			return errorf("파이프라인 이름 중복: '%s'", pc.Name)
+		}
+		names[pc.Name] = true
+		if len(pc.Outputs) == 0 {
+			return errorf("파이프라인 '%s': 출력이 없습니다", pc.Name)
+		}
+		for _, oc := range pc.Outputs {
+			if _, err := oc.Build(); err != nil {
+				return errorf("파이프라인 '%s': %w", pc.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Find 는 이름으로 파이프라인을 찾습니다 (이름이 비어 있으면 첫 번째 파이프라인).
+func (cfg Config) Find(name string) (PipelineConfig, bool) {
+	if name == "" && len(cfg.Pipelines) > 0 {
+		return cfg.Pipelines[0], true
+	}
+	for _, pc := range cfg.Pipelines {
+		if pc.Name == name {
+			return pc, true
+		}
+	}
+	return PipelineConfig{}, false
+}
+
+// Build 는 출력 정의로 파이프라인을 만듭니다.
+func (oc OutputConfig) Build() (*Pipeline, error) {
+	if oc.Name == "" {
+		return nil, errorf("출력 이름이 비어 있습니다")
+	}
+	if len(oc.Stages) == 0 {
+		return nil, errorf("출력 '%s': 단계가 없습니다", oc.Name)
+	}
+	p := NewPipeline()
+	for i, sc := range oc.Stages {
+		if err := sc.addTo(p); err != nil {
+			return nil, errorf("출력 '%s' 단계 %d (%s): %w", oc.Name, i+1, sc.Type, err)
+		}
+	}
+	return p, nil
+}
+
+// WithLoudness 는 첫 noPreamp 단계 앞에 등청감 보정 단계를 넣은 출력 정의를 반환합니다.
+// 이름과 접미사에는 청취 음량이 붙습니다 (예: Result 1 @ 60 phon, _AHTVC-By_MiFun_60phon).
+func (oc OutputConfig) WithLoudness(referencePhon, listeningPhon float64) OutputConfig {
+	level := strconv.FormatFloat(listeningPhon, 'f', -1, 64)
+	stage := StageConfig{Type: StageLoudness, RefPhon: referencePhon, ListenPhon: listeningPhon}
+	at := len(oc.Stages)
+	for i, sc := range oc.Stages {
+		if sc.Type == StageNoPreamp {
+			at = i
+			break
+		}
+	}
+	stages := make([]StageConfig, 0, len(oc.Stages)+1)
+	stages = append(stages, oc.Stages[:at]...)
+	stages = append(stages, stage)
+	stages = append(stages, oc.Stages[at:]...)
+	return OutputConfig{
+		Name:   oc.Name + " @ " + level + " phon",
+		Suffix: oc.Suffix + "_" + level + "phon",
+		Stages: stages,
+	}
+}
+
+// LoudnessFamily 는 각 출력을 청취 음량별로 펼친 파이프라인 정의를 반환합니다.
+func (pc PipelineConfig) LoudnessFamily(referencePhon float64, levels []float64) PipelineConfig {
+	family := PipelineConfig{Name: pc.Name, Description: pc.Description}
+	for _, oc := range pc.Outputs {
+		for _, level := range levels {
+			family.Outputs = append(family.Outputs, oc.WithLoudness(referencePhon, level))
+		}
+	}
+	return family
+}
+
+// 단계를 파이프라인에 추가
+func (sc StageConfig) addTo(p *Pipeline) error {
+	var method InterpMethod
+	if sc.Interpolation != "" {
+		var err error
+		if method, err = ParseInterpMethod(sc.Interpolation); err != nil {
+			return err
+		}
+		if sc.Type != StageTarget && sc.Type != StageLayer {
+			return errorf("interpolation 은 target/layer 단계에서만 사용할 수 있습니다")
+		}
+	}
+	switch sc.Type {
+	case StageTarget:
+		switch {
+		case sc.Target == TargetHarmanToVDSF:
+			p.ApplyTargetWith(HarmanToVDSF, method)
+		case sc.Target == "" && len(sc.Points) > 0:
+			if _, err := NewInterpolator(sc.Points, method); err != nil {
+				return errorf("points 가 올바르지 않습니다: %w", err)
+			}
+			p.ApplyTargetWith(CurveFromPoints(sc.Points), method)
+		default:
+			return errorf("알 수 없는 타겟 '%s' (내장 '%s' 또는 points 사용)", sc.Target, TargetHarmanToVDSF)
+		}
+	case StageSmooth:
+		if sc.Window <= 1 || sc.Window%2 == 0 {
+			return errorf("window 는 1보다 큰 홀수여야 합니다: %d", sc.Window)
+		}
+		if sc.StartFreq < 0 {
+			return errorf("startFreq 가 올바르지 않습니다: %f", sc.StartFreq)
+		}
+		p.Smooth(sc.Window, sc.StartFreq)
+	case StageLayer:
+		if sc.Name == "" || len(sc.Points) == 0 {
+			return errorf("layer 단계에는 name 과 points 가 필요합니다")
+		}
+		// 실행 중에 레이어가 조용히 건너뛰어지지 않도록 보간기를 미리 만들어 확인
+		if _, err := NewInterpolator(sc.Points, method); err != nil {
+			return errorf("points 가 올바르지 않습니다: %w", err)
+		}
+		p.ApplyLayerWith(sc.Name, append([]Point(nil), sc.Points...), method)
+	case StageLoudness:
+		if err := ValidatePhon(sc.RefPhon); err != nil {
+			return errorf("refPhon 이 올바르지 않습니다: %w", err)
+		}
+		if err := ValidatePhon(sc.ListenPhon); err != nil {
+			return errorf("listenPhon 이 올바르지 않습니다: %w", err)
+		}
+		p.Loudness(sc.RefPhon, sc.ListenPhon)
+	case StageNoPreamp:
+		opts := PreampOptions{Mode: PreampMode(sc.Mode), Headroom: sc.Headroom}
+		if err := opts.Validate(); err != nil {
+			return err
+		}
+		p.NormalizePreampWith(opts)
+	default:
+		return errorf("알 수 없는 단계 종류 '%s'", sc.Type)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package eq
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigRejectsBadPoints(t *testing.T) {
+	stage := func(points string) string {
+		return `{"pipelines": [{"name": "p", "outputs": [{"name": "o", "stages": [` + points + `]}]}]}`
+	}
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"중복 주파수", stage(`{"type": "layer", "name": "x", "points": [{"freq": 100, "gain": 1}, {"freq": 100, "gain": 2}], "interpolation": "pchip"}`)},
+		{"0 Hz", stage(`{"type": "layer", "name": "x", "points": [{"freq": 0, "gain": 1}, {"freq": 100, "gain": 2}]}`)},
+		{"내림차순", stage(`{"type": "layer", "name": "x", "points": [{"freq": 1000, "gain": 1}, {"freq": 100, "gain": 2}, {"freq": 50, "gain": 0}], "interpolation": "cubic"}`)},
+		{"타겟 음수 주파수", stage(`{"type": "target", "points": [{"freq": -20, "gain": 1}, {"freq": 100, "gain": 2}]}`)},
+	}
+	for _, tt := range tests {
+		_, err := LoadConfig(strings.NewReader(tt.config))
+		if err == nil || !strings.Contains(err.Error(), "오름차순") {
+			t.Errorf("%s: err = %v, want 주파수 오류", tt.name, err)
+		}
+	}
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("내장 정의 검증 실패: %v", err)
+	}
+}
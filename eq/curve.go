@@ -0,0 +1,104 @@
+// Package eq 는 AutoEQ GraphicEQ 곡선을 VDSF 타겟으로 변환하는 파이프라인을 제공합니다.
+//
+// 기본 사용 예:
+//
+//	source, err := eq.Parse(content)
+//	result := eq.DefaultPipeline().Run(source)
+//	fmt.Println(eq.Format(result.Curve, eq.DefaultFormatOptions))
+package eq
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Logf 는 라이브러리 경고 메시지 출력 함수입니다 (nil 이면 출력하지 않음).
+var Logf = func(format string, args ...interface{}) {
+	fmt.Printf(format, args...)
+}
+
+// 경고 출력
+func logf(format string, args ...interface{}) {
+	if Logf != nil {
+		Logf(format, args...)
+	}
+}
+
+// Curve 는 주파수(Hz) 오름차순으로 정렬된 EQ 곡선입니다 (같은 주파수는 한 번만 나타남).
+// 입력 주파수를 그대로 보존하며, 단계마다 다시 정렬하지 않도록 항상 정렬 상태를 유지합니다.
+type Curve []Point
+
+// Point 는 EQ 곡선의 한 포인트입니다.
+type Point struct {
+	Freq float64 `json:"freq"`
+	Gain float64 `json:"gain"`
+}
+
+// Freqs 는 곡선의 주파수를 오름차순으로 반환합니다.
+func (c Curve) Freqs() []float64 {
+	freqs := make([]float64, len(c))
+	for i, p := range c {
+		freqs[i] = p.Freq
+	}
+	return freqs
+}
+
+// Points 는 곡선을 주파수 오름차순 포인트 목록으로 반환합니다 (복사본).
+func (c Curve) Points() []Point {
+	return append([]Point(nil), c...)
+}
+
+// Clone 은 곡선의 복사본을 반환합니다.
+func (c Curve) Clone() Curve {
+	return append(Curve(nil), c...)
+}
+
+// Offset 은 모든 포인트에 db 만큼 더한 곡선을 반환합니다.
+func (c Curve) Offset(db float64) Curve {
+	out := make(Curve, len(c))
+	for i, p := range c {
+		out[i] = Point{Freq: p.Freq, Gain: p.Gain + db}
+	}
+	return out
+}
+
+// At 은 정확히 freq 인 포인트의 게인을 반환합니다 (없으면 found = false).
+func (c Curve) At(freq float64) (gain float64, found bool) {
+	i := sort.Search(len(c), func(i int) bool { return c[i].Freq >= freq })
+	if i < len(c) && c[i].Freq == freq {
+		return c[i].Gain, true
+	}
+	return 0, false
+}
+
+// CurveFromPoints 는 포인트 목록을 정렬해 곡선을 만듭니다 (같은 주파수는 나중 값 사용).
+func CurveFromPoints(points []Point) Curve {
+	c := append(Curve(nil), points...)
+	sort.SliceStable(c, func(i, j int) bool { return c[i].Freq < c[j].Freq })
+	out := c[:0]
+	for _, p := range c {
+		if n := len(out); n > 0 && out[n-1].Freq == p.Freq {
+			out[n-1] = p
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// SortPoints 는 포인트 목록을 주파수 오름차순으로 정렬합니다.
+func SortPoints(points []Point) {
+	sort.Slice(points, func(i, j int) bool { return points[i].Freq < points[j].Freq })
+}
+
+// FormatFreq 는 주파수를 불필요한 소수점 없이 나타냅니다 (예: 20, 20.5).
+func FormatFreq(freq float64) string {
+	return strconv.FormatFloat(freq, 'f', -1, 64)
+}
+
+// 잘못된 게인 값 확인
+func isInvalid(gain float64) bool {
+	return math.IsNaN(gain) || math.IsInf(gain, 0)
+}
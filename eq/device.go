@@ -0,0 +1,190 @@
+package eq
+
+import (
+	"path"
+	"strings"
+)
+
+// DeviceInfo 는 AutoEQ 경로/파일 이름에서 읽은 기기 정보입니다.
+//
+//	results/oratory1990/harman_over-ear_2018/Sennheiser HD 650/Sennheiser HD 650 GraphicEQ.txt
+//	-> Brand: Sennheiser, Model: HD 650, Source: oratory1990
+type DeviceInfo struct {
+	Brand   string `json:"brand,omitempty"`
+	Model   string `json:"model,omitempty"`
+	Variant string `json:"variant,omitempty"` // 이름 끝 괄호 안의 구분 (예: sample 2, Pads)
+	Source  string `json:"source,omitempty"`  // 측정 출처 (예: oratory1990, crinacle, Rtings)
+	Rig     string `json:"rig,omitempty"`     // 측정 장비 (예: 711, GRAS 43AG-7)
+	Channel string `json:"channel,omitempty"` // 측정 채널 (L, R)
+}
+
+// 파일 이름 끝에서 제거하는 AutoEQ 결과/타겟 접미사
+var deviceSuffixes = []string{
+	" GraphicEQ", " ParametricEQ", " FixedBandEQ",
+	" Graphic Filters Harman", " Graphic Filters VDSF", " Graphic Filters",
+	" target Harman", " target VDSF", " target", " (AVG)", " (Target)", " Harman", " VDSF",
+}
+
+// 채널 표기 (접미사 -> 채널)
+var deviceChannels = []struct{ Suffix, Channel string }{
+	{"(L)", "L"}, {"(R)", "R"}, {"(Left)", "L"}, {"(Right)", "R"},
+}
+
+// 이름만으로는 기기를 알 수 없는 일반적인 파일 이름
+var genericDeviceNames = map[string]bool{"result": true, "output": true, "graphic": true, "eq": true}
+
+// 두 단어 이상인 브랜드 (나머지는 첫 단어를 브랜드로 봄)
+var multiWordBrands = []string{
+	"64 Audio", "Audio Technica", "Bang & Olufsen", "Campfire Audio", "Final Audio Design", "Final Audio",
+	"Empire Ears", "Noble Audio", "Ultimate Ears", "Unique Melody",
+}
+
+// 알려진 측정 출처 (검색 키 -> 표기)
+var deviceSources = []struct{ Key, Name string }{
+	{"oratory1990", "oratory1990"}, {"crinacle", "crinacle"}, {"rtings", "Rtings"},
+	{"innerfidelity", "Innerfidelity"}, {"headphonecom", "Headphone.com"}, {"kuulokenurkka", "Kuulokenurkka"},
+	{"superreview", "Super Review"}, {"hypethesonics", "HypetheSonics"}, {"auriculares", "Auriculares Argentina"},
+}
+
+// 알려진 측정 장비 (폴더 이름 검색 키에 포함된 문자열 -> 표기)
+var deviceRigs = []struct{ Key, Name string }{
+	{"43ag", "GRAS 43AG-7"}, {"gras", "GRAS 43AG-7"}, {"5128", "B&K 5128"},
+	{"hmsii", "HMS II.3"}, {"kemar", "KEMAR"}, {"711", "711"},
+}
+
+// ParseDeviceInfo 는 AutoEQ 결과 파일 경로 (또는 파일 이름) 에서 기기 정보를 읽습니다.
+// 폴더에서 측정 출처와 장비를, 파일 이름에서 브랜드/모델/구분/채널을 찾습니다.
+func ParseDeviceInfo(filePath string) DeviceInfo {
+	parts := strings.FieldsFunc(filePath, func(r rune) bool { return r == '/' || r == '\\' })
+	if len(parts) == 0 {
+		return DeviceInfo{}
+	}
+	filename, dirs := parts[len(parts)-1], parts[:len(parts)-1]
+
+	var info DeviceInfo
+	name := filename
+	if ext := strings.ToLower(path.Ext(name)); ext == ".txt" || ext == ".csv" {
+		name = name[:len(name)-len(ext)]
+	}
+	original := name
+	for changed := true; changed; {
+		changed = false
+		for _, suffix := range deviceSuffixes {
+			if hasSuffixFold(name, suffix) {
+				name, changed = strings.TrimSpace(name[:len(name)-len(suffix)]), true
+			}
+		}
+		for _, ch := range deviceChannels {
+			if hasSuffixFold(name, ch.Suffix) {
+				name, changed = strings.TrimSpace(name[:len(name)-len(ch.Suffix)]), true
+				info.Channel = ch.Channel
+			}
+		}
+	}
+	if genericDeviceNames[strings.ToLower(name)] {
+		// "Sennheiser result" 처럼 일반 이름이면 첫 단어 사용
+		name = ""
+		if fields := strings.Fields(original); len(fields) > 0 && !genericDeviceNames[strings.ToLower(fields[0])] {
+			name = fields[0]
+		}
+	}
+
+	// 이름 끝 괄호는 구분 (예: "HD 650 (sample 2)")
+	if strings.HasSuffix(name, ")") {
+		if open := strings.LastIndex(name, " ("); open > 0 {
+			info.Variant = strings.TrimSpace(name[open+2 : len(name)-1])
+			name = strings.TrimSpace(name[:open])
+		}
+	}
+	info.Brand, info.Model = splitBrand(name)
+
+	// 기기 폴더 (파일 이름과 같은 이름) 는 출처/장비 검색에서 제외
+	if n := len(dirs); n > 0 && strings.HasPrefix(strings.ToLower(original), strings.ToLower(dirs[n-1])) {
+		dirs = dirs[:n-1]
+	}
+	for _, dir := range dirs {
+		key := deviceKey(dir)
+		if info.Source == "" {
+			for _, s := range deviceSources {
+				if key == s.Key {
+					info.Source = s.Name
+				}
+			}
+		}
+		if info.Rig == "" {
+			for _, r := range deviceRigs {
+				if strings.Contains(key, r.Key) {
+					info.Rig = r.Name
+					break
+				}
+			}
+		}
+	}
+	return info
+}
+
+// Name 은 "브랜드 모델 (구분)" 형식의 기기 이름입니다 (채널 제외).
+func (d DeviceInfo) Name() string {
+	name := strings.TrimSpace(d.Brand + " " + d.Model)
+	if d.Variant != "" {
+		name += " (" + d.Variant + ")"
+	}
+	return name
+}
+
+// FileStem 은 출력 파일 이름에 쓰는 기기 이름입니다 (채널이 있으면 "(L)" 등을 붙임, 이름이 없으면 UnknownDevice).
+// 숨김 파일이나 "." 같은 이름이 되지 않도록 앞뒤의 점과 공백은 제거합니다.
+func (d DeviceInfo) FileStem() string {
+	name := strings.Trim(d.Name(), ". ")
+	if name == "" {
+		name = "UnknownDevice"
+	}
+	if d.Channel != "" {
+		name += " (" + d.Channel + ")"
+	}
+	return name
+}
+
+// Measurement 는 측정 출처/장비/채널 설명입니다 (예: "crinacle, 711, L").
+func (d DeviceInfo) Measurement() string {
+	var fields []string
+	for _, f := range []string{d.Source, d.Rig, d.Channel} {
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// 이름을 브랜드와 모델로 나눔 (한 단어면 모델만)
+func splitBrand(name string) (brand, model string) {
+	for _, b := range multiWordBrands {
+		if hasPrefixFold(name, b+" ") {
+			return name[:len(b)], strings.TrimSpace(name[len(b):])
+		}
+	}
+	first, rest, found := strings.Cut(name, " ")
+	if !found {
+		return "", name
+	}
+	return first, strings.TrimSpace(rest)
+}
+
+// 폴더 이름 비교용 키 (소문자, 영문/숫자만)
+func deviceKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,69 @@
+package eq
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDeviceInfo(t *testing.T) {
+	tests := []struct {
+		path string
+		want DeviceInfo
+	}{
+		{
+			"results/oratory1990/harman_over-ear_2018/Sennheiser HD 650/Sennheiser HD 650 GraphicEQ.txt",
+			DeviceInfo{Brand: "Sennheiser", Model: "HD 650", Source: "oratory1990"},
+		},
+		{
+			"results/crinacle/gras_43ag-7_harman_over-ear_2018/Audio Technica ATH-M50x (sample 2)/Audio Technica ATH-M50x (sample 2) GraphicEQ.txt",
+			DeviceInfo{Brand: "Audio Technica", Model: "ATH-M50x", Variant: "sample 2", Source: "crinacle", Rig: "GRAS 43AG-7"},
+		},
+		{
+			`results\crinacle\711 in-ear\Moondrop Blessing 2 (L)\Moondrop Blessing 2 (L).csv`,
+			DeviceInfo{Brand: "Moondrop", Model: "Blessing 2", Source: "crinacle", Rig: "711", Channel: "L"},
+		},
+		{
+			"results/Rtings/avg/Sony WH-1000XM4/Sony WH-1000XM4 ParametricEQ.txt",
+			DeviceInfo{Brand: "Sony", Model: "WH-1000XM4", Source: "Rtings"},
+		},
+		{"Etymotic ER4SR v2.0 Graphic Filters Harman.txt", DeviceInfo{Brand: "Etymotic", Model: "ER4SR v2.0"}},
+		{"AHTVC_Core-By_MiFun.txt", DeviceInfo{Model: "AHTVC_Core-By_MiFun"}},
+		{"Result Graphic Filters.txt", DeviceInfo{}},
+		{"result.txt", DeviceInfo{}},
+		{"", DeviceInfo{}},
+	}
+	for _, tt := range tests {
+		if got := ParseDeviceInfo(tt.path); got != tt.want {
+			t.Errorf("ParseDeviceInfo(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDeviceFileStem(t *testing.T) {
+	tests := []struct {
+		info DeviceInfo
+		want string
+	}{
+		{DeviceInfo{Brand: "Moondrop", Model: "Blessing 2", Channel: "L"}, "Moondrop Blessing 2 (L)"},
+		{DeviceInfo{Model: ".hidden. "}, "hidden"},
+		{DeviceInfo{Model: ".."}, "UnknownDevice"},
+		{DeviceInfo{Channel: "R"}, "UnknownDevice (R)"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.FileStem(); got != tt.want {
+			t.Errorf("FileStem(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestDeviceProvenanceRoundTrip(t *testing.T) {
+	device := DeviceInfo{Brand: "Moondrop", Model: "Blessing 2", Variant: "sample 2", Source: "crinacle", Rig: "711", Channel: "R"}
+	content := FormatFile(Curve{{Freq: 20, Gain: 1}, {Freq: 1000, Gain: 0}}, DefaultFormatOptions, Provenance{Source: "x.txt", Device: device})
+	if !strings.Contains(content, "# Device: Moondrop Blessing 2 (sample 2)\n# Measurement: source=crinacle, rig=711, channel=R\n") {
+		t.Fatalf("기기 헤더가 없음:\n%s", content)
+	}
+	prov, found := ParseProvenance(content)
+	if !found || prov.Device != device {
+		t.Errorf("ParseProvenance().Device = %+v, want %+v", prov.Device, device)
+	}
+}
@@ -0,0 +1,342 @@
+package eq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 내보내기 파일 형식
+const (
+	ExportFormatGraphicEQ       = "GraphicEQ"
+	ExportFormatPoweramp        = "Poweramp JSON"
+	ExportFormatParamEQ         = "ParametricEQ"
+	ExportFormatDDC             = "JamesDSP DDC"
+	ExportFormatMiniDSP         = "miniDSP biquad"
+	ExportFormatCamilla         = "CamillaDSP YAML"
+	ExportFormatBiquadCSV       = "Biquad CSV"
+	ExportFormatPipeWire        = "PipeWire filter-chain"
+	ExportFormatCamillaPipeline = "CamillaDSP pipeline"
+)
+
+// DDC 파일에 기록할 샘플레이트
+var ddcSampleRates = []float64{44100, 48000}
+
+// 바이쿼드 계수 내보내기 (miniDSP, CamillaDSP, CSV) 샘플레이트
+var biquadSampleRates = []float64{44100, 48000, 96000}
+
+// ExportProfile 은 플레이어별 내보내기 프로필입니다.
+type ExportProfile struct {
+	ID        string  // 파일 이름 접미사
+	Name      string  // 화면 표시 이름
+	Format    string  // 파일 형식
+	Extension string  // 파일 확장자
+	MaxBands  int     // 최대 밴드(포인트) 수 (0이면 제한 없음)
+	MinGain   float64 // 최소 게인 (dB)
+	MaxGain   float64 // 최대 게인 (dB)
+	MinFreq   float64 // PEQ 밴드 배치 시작 주파수
+	MaxFreq   float64 // PEQ 밴드 배치 끝 주파수
+}
+
+// ExportFile 은 내보내기 결과 파일입니다.
+type ExportFile struct {
+	Profile  string `json:"profile"`
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// ExportProfiles 는 지원 프로필 목록입니다.
+var ExportProfiles = []ExportProfile{
+	{ID: "Wavelet", Name: "Wavelet", Format: ExportFormatGraphicEQ, Extension: ".txt", MaxBands: 127, MinGain: -30, MaxGain: 30},
+	{ID: "JamesDSP", Name: "JamesDSP (GraphicEQ)", Format: ExportFormatGraphicEQ, Extension: ".txt", MinGain: -24, MaxGain: 24},
+	{ID: "JamesDSP_DDC", Name: "JamesDSP (DDC)", Format: ExportFormatDDC, Extension: ".vdc", MaxBands: 10, MinGain: -12, MaxGain: 12, MinFreq: 31, MaxFreq: 16000},
+	{ID: "Poweramp", Name: "Poweramp", Format: ExportFormatPoweramp, Extension: ".json", MaxBands: 10, MinGain: -15, MaxGain: 15, MinFreq: 31, MaxFreq: 16000},
+	{ID: "Neutron", Name: "Neutron (PEQ)", Format: ExportFormatParamEQ, Extension: ".txt", MaxBands: 10, MinGain: -12, MaxGain: 12, MinFreq: 31, MaxFreq: 16000},
+	{ID: "miniDSP", Name: "miniDSP (biquad)", Format: ExportFormatMiniDSP, Extension: ".txt", MaxBands: 10, MinGain: -12, MaxGain: 12, MinFreq: 31, MaxFreq: 16000},
+	{ID: "CamillaDSP", Name: "CamillaDSP (biquad)", Format: ExportFormatCamilla, Extension: ".yml", MaxBands: 15, MinGain: -12, MaxGain: 12, MinFreq: 25, MaxFreq: 16000},
+	{ID: "Biquad", Name: "Biquad CSV", Format: ExportFormatBiquadCSV, Extension: ".csv", MaxBands: 15, MinGain: -12, MaxGain: 12, MinFreq: 25, MaxFreq: 16000},
+	{ID: "PipeWire", Name: "PipeWire (filter-chain)", Format: ExportFormatPipeWire, Extension: ".conf", MaxBands: 15, MinGain: -12, MaxGain: 12, MinFreq: 25, MaxFreq: 16000},
+	{ID: "CamillaDSP_Pipeline", Name: "CamillaDSP (pipeline)", Format: ExportFormatCamillaPipeline, Extension: ".yml", MaxBands: 15, MinGain: -12, MaxGain: 12, MinFreq: 25, MaxFreq: 16000},
+}
+
+// ExportAll 은 모든 프로필로 내보냅니다 (실패한 프로필은 경고 후 건너뜀).
+func ExportAll(eqData Curve, baseFilename, presetName string) []ExportFile {
+	var files []ExportFile
+	for _, profile := range ExportProfiles {
+		content, err := profile.Export(eqData, presetName)
+		if err != nil {
+			logf("경고: %s 내보내기 실패: %v\n", profile.Name, err)
+			continue
+		}
+		files = append(files, ExportFile{
+			Profile:  profile.Name,
+			Filename: fmt.Sprintf("%s_%s%s", strings.TrimSuffix(baseFilename, ".txt"), profile.ID, profile.Extension),
+			Content:  content,
+		})
+	}
+	return files
+}
+
+// Export 는 곡선을 프로필 형식으로 변환합니다 (게인 범위를 벗어난 포인트는 제한).
+func (p ExportProfile) Export(eqData Curve, presetName string) (string, error) {
+	var points []Point
+	clamped := 0
+	for _, pt := range eqData {
+		gain := pt.Gain
+		if isInvalid(gain) {
+			continue
+		}
+		if limited := clampGain(gain, p.MinGain, p.MaxGain); limited != gain {
+			gain = limited
+			clamped++
+		}
+		points = append(points, Point{Freq: pt.Freq, Gain: gain})
+	}
+	if len(points) == 0 {
+		return "", errorf("내보낼 EQ 데이터가 없습니다")
+	}
+	if clamped > 0 {
+		logf("경고: %s 게인 범위(%.0f~%.0f dB)를 벗어난 포인트 %d개를 제한했습니다.\n", p.Name, p.MinGain, p.MaxGain, clamped)
+	}
+
+	switch p.Format {
+	case ExportFormatGraphicEQ:
+		opts := DefaultFormatOptions
+		opts.MaxPoints = p.MaxBands
+		return Format(CurveFromPoints(points), opts), nil
+	case ExportFormatPoweramp:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatPowerampPreset(filters, preamp, presetName)
+	case ExportFormatParamEQ:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatParametricEQ(filters, preamp), nil
+	case ExportFormatDDC:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatDDC(filters, preamp, presetName), nil
+	case ExportFormatMiniDSP:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatMiniDSP(filters, preamp, presetName), nil
+	case ExportFormatCamilla:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatCamillaBiquads(filters, preamp, presetName), nil
+	case ExportFormatBiquadCSV:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatBiquadCSV(filters, preamp), nil
+	case ExportFormatPipeWire:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatPipeWire(filters, preamp, presetName), nil
+	case ExportFormatCamillaPipeline:
+		filters, preamp := FitFixedBandPEQ(points, p.MaxBands, p.MinFreq, p.MaxFreq, p.MinGain, p.MaxGain)
+		return formatCamillaPipeline(filters, preamp, presetName), nil
+	}
+	return "", errorf("지원하지 않는 내보내기 형식: %s", p.Format)
+}
+
+// Poweramp 프리셋 JSON
+type powerampPreset struct {
+	Name       string         `json:"name"`
+	Preamp     float64        `json:"preamp"`
+	Parametric bool           `json:"parametric"`
+	Bands      []powerampBand `json:"bands"`
+}
+
+type powerampBand struct {
+	Type      int     `json:"type"`
+	Channels  int     `json:"channels"`
+	Frequency int     `json:"frequency"`
+	Q         float64 `json:"q"`
+	Gain      float64 `json:"gain"`
+	Color     int     `json:"color"`
+}
+
+// Poweramp 밴드 타입 (피킹)
+const powerampBandPeaking = 3
+
+// Poweramp 프리셋 JSON 생성 (프리셋 배열 형식)
+func formatPowerampPreset(filters []PEQFilter, preamp float64, presetName string) (string, error) {
+	preset := powerampPreset{Name: presetName, Preamp: roundTo(preamp, 1), Parametric: true}
+	for _, f := range filters {
+		preset.Bands = append(preset.Bands, powerampBand{
+			Type:      powerampBandPeaking,
+			Frequency: int(math.Round(f.Freq)),
+			Q:         roundTo(f.Q, 2),
+			Gain:      roundTo(f.Gain, 1),
+		})
+	}
+	out, err := json.MarshalIndent([]powerampPreset{preset}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ParametricEQ 텍스트 (AutoEQ/Equalizer APO 형식, Neutron 가져오기용)
+func formatParametricEQ(filters []PEQFilter, preamp float64) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Preamp: %.1f dB\n", preamp)
+	for i, f := range filters {
+		fmt.Fprintf(&sb, "Filter %d: ON PK Fc %.0f Hz Gain %.1f dB Q %.2f\n", i+1, f.Freq, f.Gain, f.Q)
+	}
+	return sb.String()
+}
+
+// JamesDSP DDC (.vdc) 생성
+// 샘플레이트별로 b0, b1, b2, -a1, -a2 순서의 바이쿼드 계수를 나열하며, preamp는 첫 번째 필터에 포함합니다.
+func formatDDC(filters []PEQFilter, preamp float64, presetName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", presetName)
+	for _, sampleRate := range ddcSampleRates {
+		var coeffs []string
+		for _, c := range biquadsWithPreamp(filters, preamp, sampleRate) {
+			for _, v := range []float64{c.B0, c.B1, c.B2, -c.A1, -c.A2} {
+				coeffs = append(coeffs, fmt.Sprintf("%.16f", v))
+			}
+		}
+		fmt.Fprintf(&sb, "SR_%.0f:%s\n", sampleRate, strings.Join(coeffs, ","))
+	}
+	return sb.String()
+}
+
+// 샘플레이트별 바이쿼드 계수 (preamp는 첫 번째 필터의 b 계수에 포함)
+func biquadsWithPreamp(filters []PEQFilter, preamp, sampleRate float64) []Biquad {
+	preampGain := math.Pow(10, preamp/20)
+	biquads := make([]Biquad, len(filters))
+	for i, f := range filters {
+		c := f.Biquad(sampleRate)
+		if i == 0 {
+			c.B0, c.B1, c.B2 = c.B0*preampGain, c.B1*preampGain, c.B2*preampGain
+		}
+		biquads[i] = c
+	}
+	return biquads
+}
+
+// miniDSP 고급 바이쿼드 프로그래밍 형식 (샘플레이트별 블록, a1/a2 는 miniDSP 규칙에 따라 부호 반전)
+// preamp는 첫 번째 필터에 포함합니다.
+func formatMiniDSP(filters []PEQFilter, preamp float64, presetName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", presetName)
+	for _, sampleRate := range biquadSampleRates {
+		fmt.Fprintf(&sb, "\n# Sample rate: %.0f Hz\n", sampleRate)
+		for i, c := range biquadsWithPreamp(filters, preamp, sampleRate) {
+			fmt.Fprintf(&sb, "biquad%d,\nb0=%.16f,\nb1=%.16f,\nb2=%.16f,\na1=%.16f,\na2=%.16f,\n", i+1, c.B0, c.B1, c.B2, -c.A1, -c.A2)
+		}
+	}
+	return sb.String()
+}
+
+// CamillaDSP 필터 정의 (샘플레이트마다 YAML 문서 하나, Biquad Free 계수와 preamp Gain 필터)
+func formatCamillaBiquads(filters []PEQFilter, preamp float64, presetName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", presetName)
+	for _, sampleRate := range biquadSampleRates {
+		fmt.Fprintf(&sb, "---\n# samplerate: %.0f\nfilters:\n", sampleRate)
+		fmt.Fprintf(&sb, "  ahtvc_preamp:\n    type: Gain\n    parameters:\n      gain: %.2f\n", preamp)
+		for i, f := range filters {
+			c := f.Biquad(sampleRate)
+			fmt.Fprintf(&sb, "  ahtvc_peq%d:\n    type: Biquad\n    parameters:\n      type: Free\n", i+1)
+			fmt.Fprintf(&sb, "      b0: %.16f\n      b1: %.16f\n      b2: %.16f\n      a1: %.16f\n      a2: %.16f\n", c.B0, c.B1, c.B2, c.A1, c.A2)
+		}
+	}
+	return sb.String()
+}
+
+// 바이쿼드 계수 CSV (정규화 a0 = 1, 분모 1 + a1 z^-1 + a2 z^-2)
+// 샘플레이트마다 preamp 를 순수 게인 바이쿼드 (b0 = 선형 게인) 로 먼저 기록합니다.
+func formatBiquadCSV(filters []PEQFilter, preamp float64) string {
+	var sb strings.Builder
+	sb.WriteString("sample_rate,index,type,freq,gain,q,b0,b1,b2,a1,a2\n")
+	for _, sampleRate := range biquadSampleRates {
+		fmt.Fprintf(&sb, "%.0f,0,GAIN,0,%.2f,0,%.16f,0,0,0,0\n", sampleRate, preamp, math.Pow(10, preamp/20))
+		for i, f := range filters {
+			c := f.Biquad(sampleRate)
+			filterType := f.Type
+			if filterType == "" {
+				filterType = FilterPeaking
+			}
+			fmt.Fprintf(&sb, "%.0f,%d,%s,%.0f,%.2f,%.4f,%.16f,%.16f,%.16f,%.16f,%.16f\n",
+				sampleRate, i+1, filterType, f.Freq, f.Gain, f.Q, c.B0, c.B1, c.B2, c.A1, c.A2)
+		}
+	}
+	return sb.String()
+}
+
+// PipeWire filter-chain 빌트인 바이쿼드 라벨
+var pipeWireLabels = map[FilterType]string{
+	FilterPeaking:   "bq_peaking",
+	FilterLowShelf:  "bq_lowshelf",
+	FilterHighShelf: "bq_highshelf",
+}
+
+// PipeWire filter-chain 모듈 설정 (~/.config/pipewire/pipewire.conf.d/ 에 두면 EQ 가 적용된 가상 싱크가 생김)
+// preamp는 0 Hz 하이 셸프 (전 대역 게인) 노드로 넣습니다.
+func formatPipeWire(filters []PEQFilter, preamp float64, presetName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n", presetName)
+	sb.WriteString("context.modules = [\n    { name = libpipewire-module-filter-chain\n        args = {\n")
+	fmt.Fprintf(&sb, "            node.description = %s\n            media.name = %s\n", strconv.Quote("AHTVC EQ - "+presetName), strconv.Quote("AHTVC EQ - "+presetName))
+	sb.WriteString("            filter.graph = {\n                nodes = [\n")
+	fmt.Fprintf(&sb, "                    { type = builtin name = preamp label = bq_highshelf control = { \"Freq\" = 0 \"Q\" = 1.0 \"Gain\" = %.2f } }\n", preamp)
+	for i, f := range filters {
+		label := pipeWireLabels[f.Type]
+		if label == "" {
+			label = pipeWireLabels[FilterPeaking]
+		}
+		fmt.Fprintf(&sb, "                    { type = builtin name = eq_band_%d label = %s control = { \"Freq\" = %.0f \"Q\" = %.4f \"Gain\" = %.2f } }\n", i+1, label, f.Freq, f.Q, f.Gain)
+	}
+	sb.WriteString("                ]\n                links = [\n")
+	prev := "preamp"
+	for i := range filters {
+		name := fmt.Sprintf("eq_band_%d", i+1)
+		fmt.Fprintf(&sb, "                    { output = \"%s:Out\" input = \"%s:In\" }\n", prev, name)
+		prev = name
+	}
+	sb.WriteString("                ]\n            }\n")
+	sb.WriteString("            audio.channels = 2\n            audio.position = [ FL FR ]\n")
+	sb.WriteString("            capture.props = {\n                node.name = \"effect_input.ahtvc_eq\"\n                media.class = Audio/Sink\n            }\n")
+	sb.WriteString("            playback.props = {\n                node.name = \"effect_output.ahtvc_eq\"\n                node.passive = true\n            }\n")
+	sb.WriteString("        }\n    }\n]\n")
+	return sb.String()
+}
+
+// CamillaDSP 필터 종류 (파라메트릭 Biquad)
+var camillaTypes = map[FilterType]string{
+	FilterPeaking:   "Peaking",
+	FilterLowShelf:  "Lowshelf",
+	FilterHighShelf: "Highshelf",
+}
+
+// CamillaDSP 3 전체 설정 (장치, 필터, 스테레오 파이프라인)
+// 장치 항목은 일반적인 ALSA 루프백 구성이므로 환경에 맞게 수정해야 합니다.
+func formatCamillaPipeline(filters []PEQFilter, preamp float64, presetName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\ntitle: %s\n", presetName, strconv.Quote(presetName))
+	sb.WriteString("devices:\n  samplerate: 48000\n  chunksize: 1024\n")
+	sb.WriteString("  capture:\n    type: Alsa\n    channels: 2\n    device: \"hw:Loopback,1\"\n    format: S32LE\n")
+	sb.WriteString("  playback:\n    type: Alsa\n    channels: 2\n    device: \"default\"\n    format: S32LE\n")
+	sb.WriteString("filters:\n")
+	fmt.Fprintf(&sb, "  ahtvc_preamp:\n    type: Gain\n    parameters:\n      gain: %.2f\n      scale: dB\n", preamp)
+	names := []string{"ahtvc_preamp"}
+	for i, f := range filters {
+		filterType := camillaTypes[f.Type]
+		if filterType == "" {
+			filterType = camillaTypes[FilterPeaking]
+		}
+		name := fmt.Sprintf("ahtvc_peq%d", i+1)
+		names = append(names, name)
+		fmt.Fprintf(&sb, "  %s:\n    type: Biquad\n    parameters:\n      type: %s\n      freq: %.0f\n      gain: %.2f\n      q: %.4f\n", name, filterType, f.Freq, f.Gain, f.Q)
+	}
+	sb.WriteString("pipeline:\n  - type: Filter\n    channels: [0, 1]\n    names:\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "      - %s\n", name)
+	}
+	return sb.String()
+}
+
+// 소수점 자리수 반올림
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,130 @@
+package eq
+
+import (
+	"bufio"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// CSV 로 내보낸 계수를 다시 읽어 합친 응답이 PEQ 필터 + preamp 응답과 같은지 확인
+func TestExportBiquadCSV(t *testing.T) {
+	filters := []PEQFilter{
+		{Type: FilterLowShelf, Freq: 105, Gain: 5, Q: 0.7},
+		{Type: FilterPeaking, Freq: 1000, Gain: -3, Q: 1.41},
+		{Type: FilterHighShelf, Freq: 10000, Gain: -2, Q: 0.7},
+	}
+	const preamp = -5.2
+	content := formatBiquadCSV(filters, preamp)
+
+	biquads := map[float64][]Biquad{}
+	gains := map[float64]float64{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Scan() // 헤더
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 11 {
+			t.Fatalf("열 %d개: %q", len(fields), scanner.Text())
+		}
+		var v [11]float64
+		for i, field := range fields {
+			if i == 2 {
+				continue
+			}
+			f, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				t.Fatalf("%q: %v", scanner.Text(), err)
+			}
+			v[i] = f
+		}
+		if fields[2] == "GAIN" {
+			gains[v[0]] = v[6]
+			continue
+		}
+		biquads[v[0]] = append(biquads[v[0]], Biquad{B0: v[6], B1: v[7], B2: v[8], A1: v[9], A2: v[10]})
+	}
+	if len(biquads) != len(biquadSampleRates) {
+		t.Fatalf("샘플레이트 %d개, want %d", len(biquads), len(biquadSampleRates))
+	}
+	for sampleRate, coeffs := range biquads {
+		if len(coeffs) != len(filters) {
+			t.Fatalf("%g Hz: 필터 %d개, want %d", sampleRate, len(coeffs), len(filters))
+		}
+		for _, freq := range []float64{20, 105, 1000, 10000, 16000} {
+			got := 20 * math.Log10(gains[sampleRate])
+			for _, c := range coeffs {
+				got += c.ResponseDB(freq, sampleRate)
+			}
+			want := PEQResponseDB(filters, freq, sampleRate) + preamp
+			if math.Abs(got-want) > 1e-6 {
+				t.Errorf("%g Hz / %g Hz: %.6f dB, want %.6f", sampleRate, freq, got, want)
+			}
+		}
+	}
+}
+
+// miniDSP 는 a1/a2 부호가 반전되고 preamp 가 첫 번째 필터에 포함됨
+func TestExportMiniDSP(t *testing.T) {
+	filters := []PEQFilter{{Type: FilterPeaking, Freq: 1000, Gain: -3, Q: 1.41}}
+	content := formatMiniDSP(filters, -6, "test")
+	want := filters[0].Biquad(48000)
+	if !strings.Contains(content, "# Sample rate: 48000 Hz\nbiquad1,\n") {
+		t.Fatalf("48000 Hz 블록 없음:\n%s", content)
+	}
+	block := content[strings.Index(content, "# Sample rate: 48000 Hz"):]
+	values := map[string]float64{}
+	for _, line := range strings.Split(block, "\n")[2:7] {
+		name, value, _ := strings.Cut(strings.TrimSuffix(line, ","), "=")
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		values[name] = f
+	}
+	gain := math.Pow(10, -6.0/20)
+	if math.Abs(values["b0"]-want.B0*gain) > 1e-12 || math.Abs(values["a1"]+want.A1) > 1e-12 || math.Abs(values["a2"]+want.A2) > 1e-12 {
+		t.Errorf("계수 = %v, want b0=%g a1=%g a2=%g", values, want.B0*gain, -want.A1, -want.A2)
+	}
+}
+
+// PipeWire/CamillaDSP 설정: 모든 노드가 순서대로 연결되고 이름의 따옴표가 이스케이프되는지 확인
+func TestExportLinuxConfigs(t *testing.T) {
+	filters := []PEQFilter{
+		{Type: FilterLowShelf, Freq: 105, Gain: 5, Q: 0.7},
+		{Freq: 1000, Gain: -3, Q: 1.41},
+		{Type: FilterHighShelf, Freq: 10000, Gain: -2, Q: 0.7},
+	}
+	name := `My "EQ"`
+
+	pw := formatPipeWire(filters, -4, name)
+	for _, want := range []string{
+		`node.description = "AHTVC EQ - My \"EQ\""`,
+		`label = bq_highshelf control = { "Freq" = 0 "Q" = 1.0 "Gain" = -4.00 }`,
+		`name = eq_band_1 label = bq_lowshelf`,
+		`name = eq_band_2 label = bq_peaking`,
+		`name = eq_band_3 label = bq_highshelf`,
+		`{ output = "preamp:Out" input = "eq_band_1:In" }`,
+		`{ output = "eq_band_2:Out" input = "eq_band_3:In" }`,
+	} {
+		if !strings.Contains(pw, want) {
+			t.Errorf("PipeWire 설정에 %q 없음:\n%s", want, pw)
+		}
+	}
+	if strings.Count(pw, "{ output =") != len(filters) {
+		t.Errorf("링크 %d개, want %d", strings.Count(pw, "{ output ="), len(filters))
+	}
+
+	camilla := formatCamillaPipeline(filters, -4, name)
+	for _, want := range []string{
+		`title: "My \"EQ\""`,
+		"      type: Lowshelf\n      freq: 105\n",
+		"      type: Peaking\n      freq: 1000\n",
+		"      type: Highshelf\n      freq: 10000\n",
+		"    names:\n      - ahtvc_preamp\n      - ahtvc_peq1\n      - ahtvc_peq2\n      - ahtvc_peq3\n",
+	} {
+		if !strings.Contains(camilla, want) {
+			t.Errorf("CamillaDSP 설정에 %q 없음:\n%s", want, camilla)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package eq
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatOptions 는 GraphicEQ 출력 옵션입니다.
+type FormatOptions struct {
+	Precision    int     // 게인 소수점 자리수 (0~3)
+	QuantizeStep float64 // 게인 양자화 단위 (dB, 0이면 사용 안 함)
+	MinFreq      int     // 이 주파수 미만 포인트 제외 (0이면 제한 없음)
+	MaxFreq      int     // 이 주파수 초과 포인트 제외 (0이면 제한 없음)
+	MaxPoints    int     // 최대 포인트 수 (0이면 제한 없음)
+	// 게인을 내리는 대신 별도의 "Preamp:" 라인으로 출력 (Equalizer APO 형식, FormatFile 에서 사용)
+	SeparatePreamp bool
+}
+
+// DefaultFormatOptions 는 기존 출력과 동일한 기본 옵션입니다 (%.1f, 모든 포인트).
+var DefaultFormatOptions = FormatOptions{Precision: 1}
+
+// Params 는 기본값과 다른 옵션만 헤더 파라미터로 변환합니다.
+func (opts FormatOptions) Params() []Param {
+	var params []Param
+	if opts.Precision != DefaultFormatOptions.Precision {
+		params = append(params, Param{Key: "precision", Value: strconv.Itoa(opts.Precision)})
+	}
+	if opts.QuantizeStep > 0 {
+		params = append(params, Param{Key: "quantizeStep", Value: strconv.FormatFloat(opts.QuantizeStep, 'f', -1, 64)})
+	}
+	if opts.MinFreq > 0 {
+		params = append(params, Param{Key: "minFreq", Value: strconv.Itoa(opts.MinFreq)})
+	}
+	if opts.MaxFreq > 0 {
+		params = append(params, Param{Key: "maxFreq", Value: strconv.Itoa(opts.MaxFreq)})
+	}
+	if opts.MaxPoints > 0 {
+		params = append(params, Param{Key: "maxPoints", Value: strconv.Itoa(opts.MaxPoints)})
+	}
+	if opts.SeparatePreamp {
+		params = append(params, Param{Key: "separatePreamp", Value: "true"})
+	}
+	return params
+}
+
+// Format 은 곡선을 GraphicEQ 라인으로 변환합니다.
+func Format(eqData Curve, opts FormatOptions) string {
+	var resultBuffer bytes.Buffer
+	resultBuffer.WriteString("GraphicEQ: ")
+	var validPoints []Point
+	for _, p := range eqData {
+		gain := p.Gain
+		if isInvalid(gain) {
+			logf("경고: 결과 포맷팅 중 잘못된 게인 값 발견 (freq: %s). 0.0으로 대체.\n", FormatFreq(p.Freq))
+			gain = 0.0
+		}
+		validPoints = append(validPoints, Point{Freq: p.Freq, Gain: gain})
+	}
+	var eqPoints []string
+	for _, p := range selectOutputPoints(validPoints, opts) {
+		gain := quantizeGain(p.Gain, opts.QuantizeStep)
+		if gain == 0 {
+			gain = 0 // -0.0 출력 방지
+		}
+		eqPoints = append(eqPoints, fmt.Sprintf("%s %.*f", FormatFreq(p.Freq), opts.Precision, gain))
+	}
+	if len(eqPoints) > 0 {
+		resultBuffer.WriteString(strings.Join(eqPoints, "; "))
+	} else {
+		resultBuffer.Reset()
+		resultBuffer.WriteString("GraphicEQ: (No valid points)")
+	}
+	return resultBuffer.String()
+}
+
+// 게인 양자화
+func quantizeGain(gain, step float64) float64 {
+	if step <= 0 {
+		return gain
+	}
+	return math.Round(gain/step) * step
+}
+
+// 주파수 범위 제한 후 포인트 수 줄이기
+func selectOutputPoints(points []Point, opts FormatOptions) []Point {
+	var selected []Point
+	for _, p := range points {
+		if opts.MinFreq > 0 && p.Freq < float64(opts.MinFreq) {
+			continue
+		}
+		if opts.MaxFreq > 0 && p.Freq > float64(opts.MaxFreq) {
+			continue
+		}
+		selected = append(selected, p)
+	}
+	if opts.MaxPoints > 1 && len(selected) > opts.MaxPoints {
+		logf("포인트 수 %d -> %d 로 줄이는 중...\n", len(selected), opts.MaxPoints)
+		selected = Decimate(selected, opts.MaxPoints)
+	}
+	return selected
+}
+
+// Decimate 는 형태를 보존하며 포인트 수를 maxPoints 개로 줄입니다.
+// 양 끝 포인트는 유지하고, 제거했을 때 (이웃 포인트 간 로그-선형 보간 대비) 오차가 가장 작은 포인트부터 제거합니다.
+func Decimate(points []Point, maxPoints int) []Point {
+	kept := make([]Point, len(points))
+	copy(kept, points)
+	removalError := func(i int) float64 {
+		return math.Abs(kept[i].Gain - logLinearBetween(kept[i-1], kept[i+1], kept[i].Freq))
+	}
+	for len(kept) > maxPoints && len(kept) > 2 {
+		bestIndex, bestError := 1, math.MaxFloat64
+		for i := 1; i < len(kept)-1; i++ {
+			if e := removalError(i); e < bestError {
+				bestIndex, bestError = i, e
+			}
+		}
+		kept = append(kept[:bestIndex], kept[bestIndex+1:]...)
+	}
+	return kept
+}
@@ -0,0 +1,219 @@
+package eq
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// InputFormat 은 자동 감지된 입력 파일 형식입니다.
+type InputFormat string
+
+// 지원하는 입력 형식
+const (
+	InputGraphicEQ InputFormat = "GraphicEQ"  // Equalizer APO 설정 (GraphicEQ/Filter 라인)
+	InputAutoEQCSV InputFormat = "AutoEQ CSV" // AutoEQ 저장소의 frequency,raw,... CSV
+	InputSquig     InputFormat = "squig.link" // squig.link 주파수 응답 내보내기 (탭/공백 구분, 위상 선택)
+	InputREW       InputFormat = "REW"        // REW "Export measurement as text"
+)
+
+// AutoEQ CSV 에서 곡선으로 사용할 열 (앞에 있는 열 우선)
+// 목록에 없는 열 (compensated, target 등) 은 측정값으로 잘못 쓰이지 않도록 고르지 않습니다.
+var (
+	inputColumns       = []string{"equalization", "raw", "spl", "db", "magnitude", "amplitude"}
+	measurementColumns = []string{"raw", "spl", "db", "magnitude", "amplitude"}
+)
+
+// 숫자 데이터 행이 하나도 없는 입력 (지원하지 않는 형식)
+var errUnrecognizedFormat = errorf("인식할 수 없는 파일 형식입니다 (GraphicEQ, AutoEQ CSV, squig.link, REW 내보내기를 지원합니다)")
+
+// Input 은 파싱된 입력 파일입니다.
+type Input struct {
+	Curve    Curve
+	Format   InputFormat
+	Column   string         // 곡선으로 사용한 열 이름 (GraphicEQ 는 비어 있음)
+	Channels []ChannelCurve // Equalizer APO 설정의 채널별 곡선 (Curve 는 첫 번째 채널)
+}
+
+// IsEQ 는 입력이 EQ 곡선인지 (주파수 응답 측정값이 아닌지) 여부입니다.
+func (in Input) IsEQ() bool {
+	return in.Format == InputGraphicEQ || (in.Format == InputAutoEQCSV && in.Column == "equalization")
+}
+
+// Description 은 감지된 형식과 사용한 열을 사람이 읽을 수 있게 나타냅니다.
+func (in Input) Description() string {
+	if len(in.Channels) > 1 {
+		names := make([]string, len(in.Channels))
+		for i, ch := range in.Channels {
+			names[i] = ch.Channel
+		}
+		return fmt.Sprintf("%s (%s)", in.Format, strings.Join(names, ", "))
+	}
+	if in.Column == "" {
+		return string(in.Format)
+	}
+	return fmt.Sprintf("%s (%s)", in.Format, in.Column)
+}
+
+// Equalizer APO 설정 명령으로 시작하는 라인인지 확인
+func isAPOCommand(line string) bool {
+	command, _, ok := strings.Cut(line, ":")
+	if !ok {
+		return false
+	}
+	command = strings.ToLower(strings.TrimSpace(command))
+	switch {
+	case command == "graphiceq", command == "preamp", command == "channel", command == "include":
+		return true
+	case command == "filter", strings.HasPrefix(command, "filter "):
+		return true
+	}
+	return false
+}
+
+// DetectFormat 은 헤더와 열 개수로 입력 형식을 추정합니다.
+func DetectFormat(content string) InputFormat {
+	for _, line := range splitLines(content) {
+		if isAPOCommand(line) {
+			return InputGraphicEQ
+		}
+	}
+	for _, line := range splitLines(content) {
+		if line == "" {
+			continue
+		}
+		// REW 는 '*' 주석 헤더를 씀
+		if strings.HasPrefix(line, "*") {
+			return InputREW
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if strings.Contains(line, ",") {
+			return InputAutoEQCSV
+		}
+		return InputSquig
+	}
+	return InputGraphicEQ
+}
+
+// ParseInput 은 형식을 자동 감지해 입력 파일을 파싱합니다.
+// GraphicEQ 라인, AutoEQ CSV, squig.link 및 REW 텍스트 내보내기를 지원합니다.
+func ParseInput(content string) (Input, error) {
+	format := DetectFormat(content)
+	if format == InputGraphicEQ {
+		channels, err := ParseAPO(content)
+		if err != nil {
+			return Input{Format: format}, err
+		}
+		return Input{Curve: channels[0].Curve, Format: format, Channels: channels}, nil
+	}
+	points, column, err := parseTable(content, format, inputColumns)
+	if errors.Is(err, errUnrecognizedFormat) {
+		return Input{Format: format}, err
+	}
+	if err != nil {
+		return Input{Format: format}, errorf("%s 형식 파싱 오류: %w", format, err)
+	}
+	return Input{Curve: CurveFromPoints(points), Format: format, Column: column}, nil
+}
+
+// 표 형식 (주파수, 값[, 위상...]) 파싱
+// AutoEQ CSV 는 헤더에서 columns 중 먼저 있는 열을 고르고, 나머지 형식은 두 번째 열을 사용합니다.
+func parseTable(content string, format InputFormat, columns []string) ([]Point, string, error) {
+	valueIdx, column := 1, ""
+	headerDone, dataRows := false, 0
+	var points []Point
+	seen := make(map[float64]bool)
+
+	for lineNum, line := range splitLines(content) {
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "*") {
+			continue
+		}
+		fields := splitFields(line, format)
+		if len(fields) < 2 {
+			logf("경고: Line %d, 열이 부족한 라인 무시: '%s'\n", lineNum+1, line)
+			continue
+		}
+		freqFloat, errF := strconv.ParseFloat(fields[0], 64)
+		if errF != nil {
+			// 첫 번째 숫자 행 이전의 헤더 행
+			if !headerDone && format == InputAutoEQCSV {
+				valueIdx, column = pickColumn(fields, columns)
+				if valueIdx < 0 {
+					return nil, "", errorf("line %d: 사용할 수 있는 열(%s)이 없음: '%s'", lineNum+1, strings.Join(columns, ", "), line)
+				}
+			}
+			headerDone = true
+			continue
+		}
+		headerDone = true
+		dataRows++
+		if valueIdx >= len(fields) || fields[valueIdx] == "" {
+			continue
+		}
+		gain, errG := strconv.ParseFloat(fields[valueIdx], 64)
+		if errG != nil || isInvalid(gain) {
+			logf("경고: Line %d, 숫자 변환 오류 무시: '%s'\n", lineNum+1, line)
+			continue
+		}
+		freq := freqFloat
+		if isInvalid(freq) || freq <= 0 || freq > maxFreq {
+			logf("경고: Line %d, 비정상적인 주파수 값 무시: %s\n", lineNum+1, fields[0])
+			continue
+		}
+		// 같은 주파수가 반복되면 처음 값 사용
+		if seen[freq] {
+			continue
+		}
+		seen[freq] = true
+		points = append(points, Point{Freq: freq, Gain: gain})
+	}
+	if dataRows == 0 {
+		return nil, column, errUnrecognizedFormat
+	}
+	if len(points) < 2 {
+		return nil, column, errorf("유효한 데이터 포인트가 2개 미만입니다")
+	}
+	SortPoints(points)
+	return points, column, nil
+}
+
+// AutoEQ CSV 헤더에서 값 열 선택 (알려진 열이 없으면 -1)
+// "SPL(dB)" 처럼 단위가 붙은 헤더는 괄호 앞 이름으로 비교합니다.
+func pickColumn(header, columns []string) (int, string) {
+	for _, name := range columns {
+		for i, h := range header {
+			h, _, _ = strings.Cut(h, "(")
+			if i > 0 && strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, name
+			}
+		}
+	}
+	return -1, ""
+}
+
+// 형식별 열 구분 (CSV 는 빈 열을 유지)
+func splitFields(line string, format InputFormat) []string {
+	if format == InputAutoEQCSV {
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		return fields
+	}
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return r == '\t' || r == ' ' || r == ',' || r == ';'
+	})
+}
+
+// 줄 단위 분리 (CRLF 처리, 앞뒤 공백 제거)
+func splitLines(content string) []string {
+	content = strings.TrimPrefix(content, "\ufeff") // 메모장 등이 붙이는 UTF-8 BOM
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return lines
+}
@@ -0,0 +1,189 @@
+package eq
+
+import (
+	"math"
+	"sort"
+)
+
+// InterpMethod 는 로그 주파수 축에서의 보간 방식입니다.
+type InterpMethod string
+
+// 지원하는 보간 방식
+const (
+	InterpLogLinear InterpMethod = "linear" // 로그 주파수 선형 보간
+	InterpPCHIP     InterpMethod = "pchip"  // 단조 3차 에르미트 보간 (Fritsch-Carlson, 오버슈트 없음)
+	InterpCubic     InterpMethod = "cubic"  // 자연 3차 스플라인 (양 끝 2차 미분 0)
+)
+
+// InterpMethods 는 지원하는 보간 방식 목록입니다.
+var InterpMethods = []InterpMethod{InterpLogLinear, InterpPCHIP, InterpCubic}
+
+// ParseInterpMethod 는 보간 방식 이름을 확인합니다 (비어 있으면 로그-선형).
+func ParseInterpMethod(name string) (InterpMethod, error) {
+	if name == "" {
+		return InterpLogLinear, nil
+	}
+	for _, m := range InterpMethods {
+		if InterpMethod(name) == m {
+			return m, nil
+		}
+	}
+	return "", errorf("알 수 없는 보간 방식 '%s' (%v 중 선택)", name, InterpMethods)
+}
+
+// Interpolator 는 정렬된 포인트 목록을 log10(주파수) 축에서 보간합니다.
+// 범위 밖의 주파수는 가장자리 게인을 유지합니다.
+type Interpolator struct {
+	method InterpMethod
+	points []Point
+	x, y   []float64 // log10(주파수), 게인
+	slopes []float64 // 3차 에르미트 보간용 각 포인트의 기울기 (dB / decade)
+}
+
+// NewInterpolator 는 보간기를 만듭니다. points 는 주파수 오름차순이어야 합니다 (method 가 비어 있으면 로그-선형).
+func NewInterpolator(points []Point, method InterpMethod) (*Interpolator, error) {
+	if len(points) == 0 {
+		return nil, errorf("보간할 포인트가 없습니다")
+	}
+	method, err := ParseInterpMethod(string(method))
+	if err != nil {
+		return nil, err
+	}
+	it := &Interpolator{method: method, points: points}
+	it.x = make([]float64, len(points))
+	it.y = make([]float64, len(points))
+	for i, p := range points {
+		if p.Freq <= 0 || (i > 0 && p.Freq <= points[i-1].Freq) {
+			return nil, errorf("포인트 주파수는 0보다 크고 오름차순이어야 합니다 (인덱스 %d: %s Hz)", i, FormatFreq(p.Freq))
+		}
+		it.x[i], it.y[i] = math.Log10(p.Freq), p.Gain
+	}
+	if method == InterpLogLinear || len(points) < 3 {
+		// 포인트가 2개 이하이면 모든 방식이 선형 보간과 같음
+		it.method = InterpLogLinear
+		return it, nil
+	}
+	if method == InterpPCHIP {
+		it.slopes = pchipSlopes(it.x, it.y)
+	} else {
+		it.slopes = naturalSplineSlopes(it.x, it.y)
+	}
+	return it, nil
+}
+
+// At 은 freq 에서의 보간 게인입니다.
+func (it *Interpolator) At(freq float64) float64 {
+	n := len(it.x)
+	if freq <= it.points[0].Freq {
+		return it.y[0]
+	}
+	if freq >= it.points[n-1].Freq {
+		return it.y[n-1]
+	}
+	i := sort.Search(n, func(i int) bool { return it.points[i].Freq >= freq }) - 1
+	if it.points[i+1].Freq == freq {
+		return it.y[i+1]
+	}
+	if it.method == InterpLogLinear {
+		return lerp(it.x[i], it.x[i+1], it.y[i], it.y[i+1], math.Log10(freq))
+	}
+	return hermite(it.x[i], it.x[i+1], it.y[i], it.y[i+1], it.slopes[i], it.slopes[i+1], math.Log10(freq))
+}
+
+// 로그-선형 보간의 한 구간 (x 는 log10 주파수)
+func lerp(x0, x1, y0, y1, x float64) float64 {
+	return y0 + (x-x0)/(x1-x0)*(y1-y0)
+}
+
+// 두 포인트 사이 freq 의 로그-선형 보간 값 (Interpolator 의 InterpLogLinear 구간과 같은 계산)
+func logLinearBetween(lower, upper Point, freq float64) float64 {
+	return lerp(math.Log10(lower.Freq), math.Log10(upper.Freq), lower.Gain, upper.Gain, math.Log10(freq))
+}
+
+// Resample 은 곡선을 freqs 주파수 격자로 다시 샘플링합니다.
+func Resample(c Curve, freqs []float64, method InterpMethod) (Curve, error) {
+	it, err := NewInterpolator(c, method)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Point, len(freqs))
+	for i, freq := range freqs {
+		out[i] = Point{Freq: freq, Gain: it.At(freq)}
+	}
+	return CurveFromPoints(out), nil
+}
+
+// 3차 에르미트 구간 보간
+func hermite(x0, x1, y0, y1, m0, m1, x float64) float64 {
+	h := x1 - x0
+	t := (x - x0) / h
+	t2, t3 := t*t, t*t*t
+	return (2*t3-3*t2+1)*y0 + (t3-2*t2+t)*h*m0 + (-2*t3+3*t2)*y1 + (t3-t2)*h*m1
+}
+
+// PCHIP 기울기 (Fritsch-Carlson, 내부는 가중 조화 평균, 끝점은 3점 공식)
+func pchipSlopes(x, y []float64) []float64 {
+	n := len(x)
+	h := make([]float64, n-1)
+	delta := make([]float64, n-1)
+	for i := 0; i < n-1; i++ {
+		h[i] = x[i+1] - x[i]
+		delta[i] = (y[i+1] - y[i]) / h[i]
+	}
+	m := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		if delta[i-1]*delta[i] <= 0 {
+			continue // 극값에서는 기울기 0 (오버슈트 방지)
+		}
+		w1, w2 := 2*h[i]+h[i-1], h[i]+2*h[i-1]
+		m[i] = (w1 + w2) / (w1/delta[i-1] + w2/delta[i])
+	}
+	m[0] = pchipEndSlope(h[0], h[1], delta[0], delta[1])
+	m[n-1] = pchipEndSlope(h[n-2], h[n-3], delta[n-2], delta[n-3])
+	return m
+}
+
+// PCHIP 끝점 기울기 (단조성을 깨지 않도록 제한)
+func pchipEndSlope(h0, h1, d0, d1 float64) float64 {
+	m := ((2*h0+h1)*d0 - h0*d1) / (h0 + h1)
+	if math.Signbit(m) != math.Signbit(d0) || m == 0 || d0 == 0 {
+		return 0
+	}
+	if math.Signbit(d0) != math.Signbit(d1) && math.Abs(m) > 3*math.Abs(d0) {
+		return 3 * d0
+	}
+	return m
+}
+
+// 자연 3차 스플라인의 각 포인트 기울기
+// 2차 미분 M 에 대한 삼중대각 방정식을 풀고 (M0 = Mn = 0) 에르미트 기울기로 변환합니다.
+func naturalSplineSlopes(x, y []float64) []float64 {
+	n := len(x)
+	h := make([]float64, n-1)
+	for i := range h {
+		h[i] = x[i+1] - x[i]
+	}
+	// Thomas 알고리즘 (내부 포인트 1..n-2)
+	second := make([]float64, n)
+	diag := make([]float64, n)
+	rhs := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		diag[i] = 2 * (h[i-1] + h[i])
+		rhs[i] = 6 * ((y[i+1]-y[i])/h[i] - (y[i]-y[i-1])/h[i-1])
+		if i > 1 {
+			w := h[i-1] / diag[i-1]
+			diag[i] -= w * h[i-1]
+			rhs[i] -= w * rhs[i-1]
+		}
+	}
+	for i := n - 2; i >= 1; i-- {
+		second[i] = (rhs[i] - h[i]*second[i+1]) / diag[i]
+	}
+
+	m := make([]float64, n)
+	for i := 0; i < n-1; i++ {
+		m[i] = (y[i+1]-y[i])/h[i] - h[i]*(2*second[i]+second[i+1])/6
+	}
+	m[n-1] = (y[n-1]-y[n-2])/h[n-2] + h[n-2]*(second[n-2]+2*second[n-1])/6
+	return m
+}
@@ -0,0 +1,154 @@
+package eq
+
+import (
+	"math"
+	"testing"
+)
+
+// log10(주파수) = 1, 2, 3 인 포인트 (x = 0, 1, 2 에 해당하는 교과서 예제를 평행 이동)
+var peakPoints = []Point{{Freq: 10, Gain: 0}, {Freq: 100, Gain: 1}, {Freq: 1000, Gain: 0}}
+
+// 간격이 고르지 않은 포인트
+var unevenPoints = []Point{
+	{Freq: 20, Gain: 3}, {Freq: 100, Gain: -1}, {Freq: 1000, Gain: 0.5}, {Freq: 2000, Gain: 2}, {Freq: 16000, Gain: -4},
+}
+
+func TestInterpolatorReferenceValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		method InterpMethod
+		freq   float64
+		want   float64
+	}{
+		// 참조값: scipy.interpolate (CubicSpline bc_type='natural', PchipInterpolator) 와 같은 정의
+		{"선형 중간점", peakPoints, InterpLogLinear, math.Pow(10, 1.5), 0.5},
+		{"자연 스플라인 중간점", peakPoints, InterpCubic, math.Pow(10, 1.5), 0.6875},
+		{"PCHIP 중간점", peakPoints, InterpPCHIP, math.Pow(10, 1.5), 0.75},
+		{"PCHIP 단조 구간", []Point{{Freq: 10, Gain: 0}, {Freq: 100, Gain: 1}, {Freq: 1000, Gain: 3}}, InterpPCHIP, math.Pow(10, 2.5), 1.8541666666666667},
+		{"자연 스플라인 50Hz", unevenPoints, InterpCubic, 50, 0.39160504558736875},
+		{"자연 스플라인 300Hz", unevenPoints, InterpCubic, 300, -1.4097000322568398},
+		{"자연 스플라인 1.5kHz", unevenPoints, InterpCubic, 1500, 1.5126462875984998},
+		{"자연 스플라인 5kHz", unevenPoints, InterpCubic, 5000, 0.9180147283502151},
+		{"PCHIP 50Hz", unevenPoints, InterpPCHIP, 50, -0.055000508841013596},
+		{"PCHIP 300Hz", unevenPoints, InterpPCHIP, 300, -0.6051274344626083},
+		{"PCHIP 1.5kHz", unevenPoints, InterpPCHIP, 1500, 1.5167146458031309},
+		{"PCHIP 5kHz", unevenPoints, InterpPCHIP, 5000, 1.0386443800411607},
+		{"포인트 위치", unevenPoints, InterpCubic, 1000, 0.5},
+		{"범위 아래는 가장자리 유지", unevenPoints, InterpPCHIP, 10, 3},
+		{"범위 위는 가장자리 유지", unevenPoints, InterpCubic, 20000, -4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it, err := NewInterpolator(tt.points, tt.method)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := it.At(tt.freq); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("At(%g) = %.12f, want %.12f", tt.freq, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPCHIPDoesNotOvershoot(t *testing.T) {
+	step := []Point{{Freq: 100, Gain: 0}, {Freq: 200, Gain: 0}, {Freq: 400, Gain: 6}, {Freq: 800, Gain: 6}}
+	it, err := NewInterpolator(step, InterpPCHIP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := math.Inf(-1)
+	for _, freq := range LogGrid(100, 800, 48) {
+		got := it.At(freq)
+		if got < 0 || got > 6 || got < prev-1e-12 {
+			t.Fatalf("At(%g) = %g: 단조성 또는 범위를 벗어남", freq, got)
+		}
+		prev = got
+	}
+}
+
+func TestLinearInterpolation(t *testing.T) {
+	points := []Point{{Freq: 100, Gain: 0}, {Freq: 1000, Gain: 10}, {Freq: 10000, Gain: -10}}
+	tests := []struct{ freq, want float64 }{
+		{50, 0},                      // 범위 밖은 가장자리 값
+		{100, 0},                     // 포인트 위
+		{math.Sqrt(100 * 1000), 5},   // 로그 축 중간
+		{math.Sqrt(1000 * 10000), 0}, // 로그 축 중간
+		{20000, -10},
+	}
+	for _, tt := range tests {
+		if got := linearAt(t, points, tt.freq); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("At(%g) = %g, want %g", tt.freq, got, tt.want)
+		}
+	}
+	if _, err := NewInterpolator([]Point{{Freq: 0, Gain: 1}, {Freq: 100, Gain: 0}}, InterpLogLinear); err == nil {
+		t.Error("로그-선형 보간에서 0 Hz 포인트가 허용됨")
+	}
+}
+
+// 테스트용 로그-선형 보간
+func linearAt(tb testing.TB, points []Point, freq float64) float64 {
+	tb.Helper()
+	it, err := NewInterpolator(points, InterpLogLinear)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return it.At(freq)
+}
+
+func TestNewInterpolatorErrors(t *testing.T) {
+	if _, err := NewInterpolator(nil, InterpCubic); err == nil {
+		t.Error("빈 포인트 목록이 허용됨")
+	}
+	if _, err := NewInterpolator(unevenPoints, "akima"); err == nil {
+		t.Error("알 수 없는 보간 방식이 허용됨")
+	}
+	unsorted := []Point{{Freq: 100, Gain: 0}, {Freq: 50, Gain: 1}, {Freq: 200, Gain: 2}}
+	if _, err := NewInterpolator(unsorted, InterpPCHIP); err == nil {
+		t.Error("정렬되지 않은 포인트가 허용됨")
+	}
+}
+
+func TestResampleKeepsGrid(t *testing.T) {
+	grid := []float64{20, 55.5, 1000, 16000}
+	out, err := Resample(Curve(unevenPoints), grid, InterpCubic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != len(grid) {
+		t.Fatalf("len = %d, want %d", len(out), len(grid))
+	}
+	for i, p := range out {
+		if p.Freq != grid[i] {
+			t.Errorf("out[%d].Freq = %g, want %g", i, p.Freq, grid[i])
+		}
+	}
+}
+
+// Decimate 는 Interpolator 로 이웃 포인트 사이를 보간했을 때 오차가 가장 작은 포인트부터 제거
+func TestDecimateMatchesInterpolator(t *testing.T) {
+	points := []Point{{20, 0}, {50, 3}, {100, 1}, {300, 1.2}, {1000, -2}, {3000, 4}, {10000, 0}}
+	bestIndex, bestError := 0, math.MaxFloat64
+	for i := 1; i < len(points)-1; i++ {
+		e := math.Abs(points[i].Gain - linearAt(t, []Point{points[i-1], points[i+1]}, points[i].Freq))
+		if e < bestError {
+			bestIndex, bestError = i, e
+		}
+	}
+	got := Decimate(points, len(points)-1)
+	want := append(append([]Point(nil), points[:bestIndex]...), points[bestIndex+1:]...)
+	if len(got) != len(want) {
+		t.Fatalf("Decimate = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Decimate 가 %g Hz 대신 다른 포인트를 제거함: %v", points[bestIndex].Freq, got)
+		}
+	}
+	for _, p := range points[1 : len(points)-1] {
+		lower, upper := points[0], points[len(points)-1]
+		if got, want := logLinearBetween(lower, upper, p.Freq), linearAt(t, []Point{lower, upper}, p.Freq); math.Abs(got-want) > 1e-12 {
+			t.Errorf("logLinearBetween(%g) = %g, Interpolator = %g", p.Freq, got, want)
+		}
+	}
+}
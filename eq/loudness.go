@@ -0,0 +1,123 @@
+package eq
+
+import (
+	"math"
+)
+
+// 체감 음량 계산 범위와 해상도 (핑크 노이즈: 옥타브당 같은 에너지)
+const (
+	loudnessLowFreq         = 20.0
+	loudnessHighFreq        = 20000.0
+	loudnessPointsPerOctave = 12
+)
+
+// ITU468WeightingDB 는 ITU-R 468 가중치 (dB, 6.3 kHz 에서 +12.2 dB) 입니다.
+func ITU468WeightingDB(freq float64) float64 {
+	f2 := freq * freq
+	f3 := f2 * freq
+	f4 := f2 * f2
+	h1 := -4.737338981378384e-24*f4*f2 + 2.043828333606125e-15*f4 - 1.363894795463638e-07*f2 + 1
+	h2 := 1.306612257412824e-19*f4*freq - 2.118150887518656e-11*f3 + 5.559488023498642e-04*freq
+	r := 1.246332637532143e-4 * freq / math.Hypot(h1, h2)
+	return 18.2 + 20*math.Log10(r)
+}
+
+// PerceivedLevel 은 핑크 노이즈를 EQ 곡선에 통과시켰을 때의 ITU-R 468 가중 음량입니다 (dB).
+// 0 dB 평탄 곡선이 0 dB 가 되도록 정규화되어 있어 출력 간 상대 음량 비교에 사용합니다.
+func PerceivedLevel(c Curve) float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	// 재생 장치가 GraphicEQ 를 적용하는 방식과 같은 로그-선형 보간
+	it, err := NewInterpolator(c, InterpLogLinear)
+	if err != nil {
+		logf("경고: 체감 음량 계산 실패 (%v).\n", err)
+		return 0
+	}
+	var weighted, reference float64
+	for _, freq := range LogGrid(loudnessLowFreq, loudnessHighFreq, loudnessPointsPerOctave) {
+		w := math.Pow(10, ITU468WeightingDB(freq)/10)
+		weighted += w * math.Pow(10, it.At(freq)/10)
+		reference += w
+	}
+	return 10 * math.Log10(weighted/reference)
+}
+
+// LevelMatch 는 곡선들의 체감 음량을 가장 작은 곡선에 맞추는 오프셋(dB, 0 이하)을 반환합니다.
+// 게인을 내리기만 하므로 새로운 클리핑이 생기지 않습니다.
+func LevelMatch(curves []Curve) []float64 {
+	levels := make([]float64, len(curves))
+	quietest := math.MaxFloat64
+	for i, c := range curves {
+		levels[i] = PerceivedLevel(c)
+		quietest = math.Min(quietest, levels[i])
+	}
+	offsets := make([]float64, len(curves))
+	for i := range curves {
+		offsets[i] = quietest - levels[i]
+	}
+	return offsets
+}
+
+// ISO 226:2003 등청감 곡선 유효 범위 (phon)
+const (
+	MinPhon = 20.0
+	MaxPhon = 90.0
+)
+
+// DefaultReferencePhon 은 기본 기준 음량입니다 (믹싱/측정 기준 레벨).
+const DefaultReferencePhon = 80.0
+
+// ISO 226:2003 표 1 (주파수, 지수 αf, 크기 선형 전달 함수 LU, 가청 한계 TF)
+var iso226Table = []struct {
+	Freq, Af, Lu, Tf float64
+}{
+	{20, 0.532, -31.6, 78.5}, {25, 0.506, -27.2, 68.7}, {31.5, 0.480, -23.0, 59.5},
+	{40, 0.455, -19.1, 51.1}, {50, 0.432, -15.9, 44.0}, {63, 0.409, -13.0, 37.5},
+	{80, 0.387, -10.3, 31.5}, {100, 0.367, -8.1, 26.5}, {125, 0.349, -6.2, 22.1},
+	{160, 0.330, -4.5, 17.9}, {200, 0.315, -3.1, 14.4}, {250, 0.301, -2.0, 11.4},
+	{315, 0.288, -1.1, 8.6}, {400, 0.276, -0.4, 6.2}, {500, 0.267, 0.0, 4.4},
+	{630, 0.259, 0.3, 3.0}, {800, 0.253, 0.5, 2.2}, {1000, 0.250, 0.0, 2.4},
+	{1250, 0.246, -2.7, 3.5}, {1600, 0.244, -4.1, 1.7}, {2000, 0.243, -1.0, -1.3},
+	{2500, 0.243, 1.7, -4.2}, {3150, 0.243, 2.5, -6.0}, {4000, 0.242, 1.2, -5.4},
+	{5000, 0.242, -2.1, -1.5}, {6300, 0.245, -7.1, 6.0}, {8000, 0.254, -11.2, 12.6},
+	{10000, 0.271, -10.7, 13.9}, {12500, 0.301, -3.1, 12.3},
+}
+
+// EqualLoudnessContour 는 phon 음량의 ISO 226:2003 등청감 곡선 (주파수별 음압 dB SPL) 입니다.
+func EqualLoudnessContour(phon float64) []Point {
+	points := make([]Point, len(iso226Table))
+	for i, row := range iso226Table {
+		af := 4.47e-3*(math.Pow(10, 0.025*phon)-1.15) + math.Pow(0.4*math.Pow(10, (row.Tf+row.Lu)/10-9), row.Af)
+		points[i] = Point{Freq: row.Freq, Gain: 10/row.Af*math.Log10(af) - row.Lu + 94}
+	}
+	return points
+}
+
+// LoudnessCompensationLayer 는 기준 음량에서 들리던 균형을 청취 음량에서 유지하기 위한 보정 레이어입니다.
+// 두 등청감 곡선을 1 kHz 기준으로 정규화한 차이이며, 청취 음량이 낮을수록 저음과 고음이 올라갑니다.
+func LoudnessCompensationLayer(referencePhon, listeningPhon float64) []Point {
+	ref := EqualLoudnessContour(referencePhon)
+	listen := EqualLoudnessContour(listeningPhon)
+	layer := make([]Point, len(ref))
+	for i := range ref {
+		layer[i] = Point{
+			Freq: ref[i].Freq,
+			Gain: (listen[i].Gain - listeningPhon) - (ref[i].Gain - referencePhon),
+		}
+	}
+	return layer
+}
+
+// ValidatePhon 은 ISO 226 유효 범위를 확인합니다.
+func ValidatePhon(phon float64) error {
+	if isInvalid(phon) || phon < MinPhon || phon > MaxPhon {
+		return errorf("음량 %g phon 이 ISO 226 유효 범위(%g~%g phon)를 벗어났습니다", phon, MinPhon, MaxPhon)
+	}
+	return nil
+}
+
+// ApplyLoudnessCompensation 은 등청감 보정 레이어를 곡선에 더합니다 (PCHIP 보간, 12.5 kHz 이상은 가장자리 값 유지).
+func ApplyLoudnessCompensation(c Curve, referencePhon, listeningPhon float64) Curve {
+	return ApplyLayerWith(c, LoudnessCompensationLayer(referencePhon, listeningPhon), InterpPCHIP)
+}
@@ -0,0 +1,59 @@
+package eq
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Message 는 번역할 수 있는 오류 메시지입니다.
+// 형식 문자열(한국어)이 번역 카탈로그의 키이며, Args 는 형식에 채울 값입니다 (오류 값은 따로 번역할 수 있음).
+type Message struct {
+	Format string
+	Args   []interface{}
+}
+
+// Error 는 기본 언어(한국어)로 만든 메시지입니다.
+func (m *Message) Error() string {
+	return fmt.Sprintf(strings.ReplaceAll(m.Format, "%w", "%v"), m.Args...)
+}
+
+// Unwrap 은 Args 중 오류 값을 반환합니다 (errors.Is/As 용).
+func (m *Message) Unwrap() []error {
+	var errs []error
+	for _, arg := range m.Args {
+		if err, ok := arg.(error); ok {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Localize 는 오류 메시지를 translate 로 번역합니다.
+// translate 는 형식 문자열을 받아 번역된 형식 문자열을 반환하고, Message 가 아닌 오류는 그대로 출력합니다.
+func Localize(err error, translate func(format string) string) string {
+	if err == nil {
+		return ""
+	}
+	m, ok := err.(*Message)
+	if !ok {
+		// 메시지를 바꾸지 않고 감싸기만 한 오류는 안쪽 오류를 번역
+		if inner := errors.Unwrap(err); inner != nil && inner.Error() == err.Error() {
+			return Localize(inner, translate)
+		}
+		return err.Error()
+	}
+	args := make([]interface{}, len(m.Args))
+	for i, arg := range m.Args {
+		if inner, ok := arg.(error); ok {
+			arg = Localize(inner, translate)
+		}
+		args[i] = arg
+	}
+	return fmt.Sprintf(strings.ReplaceAll(translate(m.Format), "%w", "%v"), args...)
+}
+
+// 번역할 수 있는 오류 생성 (fmt.Errorf 와 같은 형식, %w 로 감싼 오류도 번역됨)
+func errorf(format string, args ...interface{}) error {
+	return &Message{Format: format, Args: args}
+}
@@ -0,0 +1,58 @@
+package eq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 허용 주파수 상한 (Hz)
+const maxFreq = 30000
+
+// MustParse 는 Parse 와 같지만 오류가 발생하면 panic 합니다 (상수 EQ 데이터용).
+func MustParse(content string) Curve {
+	data, err := Parse(content)
+	if err != nil {
+		panic(fmt.Sprintf("상수 EQ 데이터 '%s...' 파싱 실패: %v", content[:min(30, len(content))], err))
+	}
+	return data
+}
+
+// ParseWithProvenance 는 입력 파일 (형식 자동 감지) 과 AHTVC 출처 헤더를 함께 파싱합니다.
+func ParseWithProvenance(content string) (Input, Provenance, error) {
+	input, err := ParseInput(content)
+	if err != nil {
+		return input, Provenance{}, err
+	}
+	prov, _ := ParseProvenance(content)
+	return input, prov, nil
+}
+
+// Parse 는 AutoEQ GraphicEQ 파일 (Equalizer APO 설정) 을 곡선 하나로 파싱합니다.
+// 여러 GraphicEQ/Filter 라인은 합산하며, 채널마다 EQ 가 다르면 첫 번째 채널의 곡선을 반환합니다 (채널별 곡선은 ParseAPO).
+func Parse(content string) (Curve, error) {
+	channels, err := ParseAPO(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) > 1 {
+		logf("경고: 채널마다 EQ 가 다릅니다. 첫 번째 채널 (%s) 만 사용합니다.\n", channels[0].Channel)
+	}
+	return channels[0].Curve, nil
+}
+
+// ParseMeasurement 는 주파수 응답 측정값을 정렬된 포인트 목록으로 파싱합니다.
+// AutoEQ CSV (raw 열), squig.link, REW 내보내기 및 주파수, 값 형식의 CSV/공백 구분 파일을 지원합니다.
+func ParseMeasurement(content string) ([]Point, error) {
+	format := DetectFormat(content)
+	if format == InputGraphicEQ {
+		return nil, errorf("GraphicEQ 파일은 측정값으로 사용할 수 없습니다 (주파수 응답 파일을 선택하세요)")
+	}
+	points, _, err := parseTable(content, format, measurementColumns)
+	if errors.Is(err, errUnrecognizedFormat) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errorf("측정값 %s 형식 파싱 오류: %w", format, err)
+	}
+	return points, nil
+}
@@ -0,0 +1,146 @@
+package eq
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// 테스트 중 라이브러리 경고 출력 끄기
+func silenceLogs(tb testing.TB) {
+	tb.Helper()
+	saved := Logf
+	Logf = nil
+	tb.Cleanup(func() { Logf = saved })
+}
+
+func TestParseEdgeCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Curve
+		wantErr bool
+	}{
+		{"기본", "GraphicEQ: 20 1; 1000 0; 20000 -2", Curve{{20, 1}, {1000, 0}, {20000, -2}}, false},
+		{"CRLF", "# 주석\r\nGraphicEQ: 20 1; 1000 0\r\n", Curve{{20, 1}, {1000, 0}}, false},
+		{"UTF-8 BOM", "\ufeffGraphicEQ: 20 1; 1000 0", Curve{{20, 1}, {1000, 0}}, false},
+		{"끝 세미콜론", "GraphicEQ: 20 1; 1000 0;", Curve{{20, 1}, {1000, 0}}, false},
+		{"끝 세미콜론과 공백", "GraphicEQ: 20 1; 1000 0;  \n", Curve{{20, 1}, {1000, 0}}, false},
+		{"중간 빈 포인트", "GraphicEQ: 20 1;; 1000 0", Curve{{20, 1}, {1000, 0}}, false},
+		{"여러 GraphicEQ 라인은 합산", "GraphicEQ: 20 1; 1000 0\nGraphicEQ: 20 5; 1000 5", Curve{{20, 6}, {1000, 5}}, false},
+		{"앞쪽 주석과 Preamp", "# AutoEQ\n// note\nPreamp: -6 dB\nGraphicEQ: 20 1", Curve{{20, 1}}, false},
+		{"NaN 게인 무시", "GraphicEQ: 20 NaN; 1000 0", Curve{{1000, 0}}, false},
+		{"Inf 게인 무시", "GraphicEQ: 20 +Inf; 1000 -Inf; 2000 1", Curve{{2000, 1}}, false},
+		{"NaN 주파수 무시", "GraphicEQ: NaN 1; 1000 0", Curve{{1000, 0}}, false},
+		{"범위 밖 주파수 무시", "GraphicEQ: 0 1; -20 1; 40000 1; 1000 0", Curve{{1000, 0}}, false},
+		{"잘못된 포인트 형식 무시", "GraphicEQ: 20; 30 1 2; abc 1; 1000 0", Curve{{1000, 0}}, false},
+		{"정렬과 중복 제거", "GraphicEQ: 1000 0; 20 1; 1000 2", Curve{{20, 1}, {1000, 2}}, false},
+		{"모든 포인트가 NaN", "GraphicEQ: 20 NaN; 1000 NaN", nil, true},
+		{"빈 GraphicEQ 라인", "GraphicEQ:", nil, true},
+		{"GraphicEQ 라인 없음", "Preamp: -6 dB\n", nil, true},
+		{"빈 파일", "", nil, true},
+	}
+	silenceLogs(t)
+	for _, tt := range tests {
+		got, err := Parse(tt.content)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: Parse() = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: Parse() = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"GraphicEQ: 20 1; 1000 0; 20000 -2",
+		"# 주석\r\nGraphicEQ: 20 1; 1000 0;\r\n",
+		"GraphicEQ: 20 NaN; 1000 +Inf;; 0 1",
+		"Preamp: -6 dB\nGraphicEQ: 1e3 1e400",
+		"frequency,raw,equalization\n20,0,1\n1000,0,0\n",
+	} {
+		f.Add(seed)
+	}
+	silenceLogs(f)
+	f.Fuzz(func(t *testing.T, content string) {
+		curve, err := Parse(content)
+		if err != nil {
+			if curve != nil {
+				t.Fatalf("오류와 함께 곡선 반환: %v", curve)
+			}
+			return
+		}
+		if len(curve) == 0 {
+			t.Fatal("오류 없이 빈 곡선 반환")
+		}
+		for i, p := range curve {
+			if math.IsNaN(p.Gain) || math.IsInf(p.Gain, 0) || !(p.Freq > 0 && p.Freq <= maxFreq) {
+				t.Fatalf("잘못된 포인트 %d: %+v", i, p)
+			}
+			if i > 0 && curve[i-1].Freq >= p.Freq {
+				t.Fatalf("정렬되지 않은 곡선: %v", curve)
+			}
+		}
+	})
+}
+
+func FuzzParseInput(f *testing.F) {
+	for _, seed := range []string{
+		"GraphicEQ: 20 1; 1000 0",
+		"frequency,raw,equalization\n20,0,1\n1000,0,0\n",
+		"* REW\nFreq(Hz) SPL(dB) Phase(degrees)\n20 80 0\n1000 75 0\n",
+		"20\t80\n1000\t75\n",
+	} {
+		f.Add(seed)
+	}
+	silenceLogs(f)
+	f.Fuzz(func(t *testing.T, content string) {
+		input, err := ParseInput(content)
+		if err != nil {
+			return
+		}
+		for i, p := range input.Curve {
+			if math.IsNaN(p.Gain) || math.IsInf(p.Gain, 0) {
+				t.Fatalf("잘못된 게인 %d: %+v", i, p)
+			}
+			if i > 0 && input.Curve[i-1].Freq >= p.Freq {
+				t.Fatalf("정렬되지 않은 곡선: %v", input.Curve)
+			}
+		}
+	})
+}
+
+func TestParseMeasurementColumns(t *testing.T) {
+	silenceLogs(t)
+	points, err := ParseMeasurement("frequency,target,raw\n20,0,80\n1000,0,75\n")
+	if err != nil || len(points) != 2 || points[0].Gain != 80 {
+		t.Errorf("raw 열 = %v, %v", points, err)
+	}
+	points, err = ParseMeasurement("frequency,SPL(dB)\n20,80\n1000,75\n")
+	if err != nil || len(points) != 2 {
+		t.Errorf("SPL 열 = %v, %v", points, err)
+	}
+	// raw 열이 없으면 보정/타겟 열로 대신하지 않음
+	if _, err := ParseMeasurement("frequency,compensated,target\n20,1,0\n1000,2,0\n"); err == nil || !strings.Contains(err.Error(), "사용할 수 있는 열") {
+		t.Errorf("raw 열 없는 CSV: err = %v", err)
+	}
+	for _, content := range []string{"hello world\nthis is not a measurement\n", "<html><body>oops</body></html>"} {
+		_, err := ParseMeasurement(content)
+		if !errors.Is(err, errUnrecognizedFormat) {
+			t.Errorf("ParseMeasurement(%q): err = %v, want 인식할 수 없는 형식", content, err)
+		}
+		if _, err := ParseInput(content); !errors.Is(err, errUnrecognizedFormat) {
+			t.Errorf("ParseInput(%q): err = %v, want 인식할 수 없는 형식", content, err)
+		}
+	}
+}
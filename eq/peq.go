@@ -0,0 +1,138 @@
+package eq
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// 고정 대역 PEQ 근사 반복 횟수
+const peqFitIterations = 30
+
+// FilterType 은 PEQ 필터 종류입니다 (Equalizer APO 표기).
+type FilterType string
+
+// 지원하는 필터 종류
+const (
+	FilterPeaking   FilterType = "PK"
+	FilterLowShelf  FilterType = "LSC"
+	FilterHighShelf FilterType = "HSC"
+)
+
+// PEQFilter 는 피킹/셸빙 필터입니다 (RBJ Audio EQ Cookbook, Type 이 비어 있으면 피킹).
+type PEQFilter struct {
+	Type FilterType
+	Freq float64
+	Gain float64
+	Q    float64
+}
+
+// Biquad 는 정규화된 바이쿼드 계수입니다 (a0 = 1).
+type Biquad struct {
+	B0, B1, B2, A1, A2 float64
+}
+
+// Biquad 는 샘플레이트에 맞는 필터 바이쿼드 계수를 계산합니다.
+func (f PEQFilter) Biquad(sampleRate float64) Biquad {
+	a := math.Pow(10, f.Gain/40)
+	w0 := 2 * math.Pi * f.Freq / sampleRate
+	alpha := math.Sin(w0) / (2 * f.Q)
+	cosW0 := math.Cos(w0)
+	switch f.Type {
+	case FilterLowShelf, FilterHighShelf:
+		return shelfBiquad(f.Type == FilterHighShelf, a, alpha, cosW0)
+	}
+	a0 := 1 + alpha/a
+	return Biquad{
+		B0: (1 + alpha*a) / a0,
+		B1: -2 * cosW0 / a0,
+		B2: (1 - alpha*a) / a0,
+		A1: -2 * cosW0 / a0,
+		A2: (1 - alpha/a) / a0,
+	}
+}
+
+// 셸빙 필터 계수 (high 이면 하이 셸프)
+func shelfBiquad(high bool, a, alpha, cosW0 float64) Biquad {
+	sign := 1.0
+	if high {
+		sign = -1
+	}
+	sqrtA2alpha := 2 * math.Sqrt(a) * alpha
+	a0 := (a + 1) + sign*(a-1)*cosW0 + sqrtA2alpha
+	return Biquad{
+		B0: a * ((a + 1) - sign*(a-1)*cosW0 + sqrtA2alpha) / a0,
+		B1: sign * 2 * a * ((a - 1) - sign*(a+1)*cosW0) / a0,
+		B2: a * ((a + 1) - sign*(a-1)*cosW0 - sqrtA2alpha) / a0,
+		A1: -sign * 2 * ((a - 1) + sign*(a+1)*cosW0) / a0,
+		A2: ((a + 1) + sign*(a-1)*cosW0 - sqrtA2alpha) / a0,
+	}
+}
+
+// ResponseDB 는 주파수 freq 에서의 크기 응답(dB)입니다.
+func (c Biquad) ResponseDB(freq, sampleRate float64) float64 {
+	z := cmplx.Exp(complex(0, -2*math.Pi*freq/sampleRate))
+	num := complex(c.B0, 0) + complex(c.B1, 0)*z + complex(c.B2, 0)*z*z
+	den := 1 + complex(c.A1, 0)*z + complex(c.A2, 0)*z*z
+	return 20 * math.Log10(cmplx.Abs(num/den))
+}
+
+// PEQResponseDB 는 필터 묶음의 합성 응답(dB)입니다.
+func PEQResponseDB(filters []PEQFilter, freq, sampleRate float64) float64 {
+	total := 0.0
+	for _, f := range filters {
+		total += f.Biquad(sampleRate).ResponseDB(freq, sampleRate)
+	}
+	return total
+}
+
+// FitFixedBandPEQ 는 고정 대역 PEQ 로 곡선을 근사합니다.
+// minFreq~maxFreq 사이에 로그 간격으로 bandCount 개의 피킹 필터를 배치하고,
+// 중심 주파수에서의 오차가 줄어들도록 게인을 반복 보정합니다. 반환되는 preamp는 곡선 평균 레벨입니다.
+func FitFixedBandPEQ(points []Point, bandCount int, minFreq, maxFreq, minGain, maxGain float64) ([]PEQFilter, float64) {
+	if bandCount <= 0 || len(points) == 0 {
+		return nil, 0
+	}
+	centers := make([]float64, bandCount)
+	q := math.Sqrt2
+	if bandCount == 1 {
+		centers[0] = math.Sqrt(minFreq * maxFreq)
+	} else {
+		bandwidth := math.Log2(maxFreq/minFreq) / float64(bandCount-1)
+		q = math.Sqrt(math.Pow(2, bandwidth)) / (math.Pow(2, bandwidth) - 1)
+		for i := range centers {
+			centers[i] = minFreq * math.Pow(2, bandwidth*float64(i))
+		}
+	}
+
+	// 밴드 중심의 목표 게인 (GraphicEQ 와 같은 로그-선형 보간)
+	it, err := NewInterpolator(points, InterpLogLinear)
+	if err != nil {
+		logf("경고: PEQ 근사 실패 (%v).\n", err)
+		return nil, 0
+	}
+	targets := make([]float64, bandCount)
+	preamp := 0.0
+	for i, c := range centers {
+		targets[i] = it.At(c)
+		preamp += targets[i]
+	}
+	preamp /= float64(bandCount)
+
+	filters := make([]PEQFilter, bandCount)
+	for i, c := range centers {
+		filters[i] = PEQFilter{Freq: c, Gain: clampGain(targets[i]-preamp, minGain, maxGain), Q: q}
+	}
+	const fitSampleRate = 48000.0
+	for iter := 0; iter < peqFitIterations; iter++ {
+		for i, c := range centers {
+			residual := (targets[i] - preamp) - PEQResponseDB(filters, c, fitSampleRate)
+			filters[i].Gain = clampGain(filters[i].Gain+0.5*residual, minGain, maxGain)
+		}
+	}
+	return filters, preamp
+}
+
+// 게인 제한
+func clampGain(gain, minGain, maxGain float64) float64 {
+	return math.Max(minGain, math.Min(maxGain, gain))
+}
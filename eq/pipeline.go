@@ -0,0 +1,199 @@
+package eq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Param 은 파이프라인 파라미터입니다 (출력 헤더에 순서대로 기록).
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Stage 는 파이프라인의 한 단계입니다.
+// Apply 는 변환된 곡선과 이 단계에서 제거한 Preamp 이동량(dB)을 반환합니다.
+type Stage struct {
+	Name   string
+	Params []Param
+	Apply  func(Curve) (Curve, float64)
+}
+
+// Pipeline 은 순서대로 적용할 단계 목록입니다.
+type Pipeline struct {
+	stages []Stage
+}
+
+// Result 는 파이프라인 실행 결과입니다.
+type Result struct {
+	Curve       Curve   // 최종 곡선
+	PreampShift float64 // 제거된 Preamp 이동량 합계 (dB)
+	Params      []Param // 출력 헤더용 파라미터
+}
+
+// NewPipeline 은 빈 파이프라인을 만듭니다.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Stage 는 사용자 정의 단계를 추가합니다.
+func (p *Pipeline) Stage(s Stage) *Pipeline {
+	p.stages = append(p.stages, s)
+	return p
+}
+
+// ApplyTarget 은 타겟 차이 곡선을 더하는 단계를 추가합니다.
+func (p *Pipeline) ApplyTarget(offset Curve) *Pipeline {
+	return p.Stage(Stage{
+		Name: "offset",
+		Apply: func(c Curve) (Curve, float64) {
+			return ApplyTarget(c, offset), 0
+		},
+	})
+}
+
+// ApplyTargetWith 는 타겟 차이 곡선을 소스 주파수로 보간해 더하는 단계를 추가합니다.
+// method 가 비어 있으면 ApplyTarget 과 같습니다 (두 곡선의 주파수 합집합).
+func (p *Pipeline) ApplyTargetWith(offset Curve, method InterpMethod) *Pipeline {
+	if method == "" {
+		return p.ApplyTarget(offset)
+	}
+	return p.Stage(Stage{
+		Name:   "offset",
+		Params: []Param{{Key: "targetInterpolation", Value: string(method)}},
+		Apply: func(c Curve) (Curve, float64) {
+			result, err := ApplyTargetResampled(c, offset, method)
+			if err != nil {
+				logf("경고: 타겟 보간 실패 (%v). 합집합 방식으로 적용합니다.\n", err)
+				return ApplyTarget(c, offset), 0
+			}
+			return result, 0
+		},
+	})
+}
+
+// Smooth 는 이동 평균 스무딩 단계를 추가합니다.
+func (p *Pipeline) Smooth(windowSize int, startFreq float64) *Pipeline {
+	return p.Stage(Stage{
+		Name: "smooth",
+		Params: []Param{
+			{Key: "smoothStartFreq", Value: strconv.FormatFloat(startFreq, 'f', -1, 64)},
+			{Key: "movingAverageWindow", Value: strconv.Itoa(windowSize)},
+		},
+		Apply: func(c Curve) (Curve, float64) {
+			return Smooth(c, windowSize, startFreq), 0
+		},
+	})
+}
+
+// ApplyLayer 는 이름이 있는 EQ 레이어를 더하는 단계를 추가합니다 (로그-선형 보간).
+func (p *Pipeline) ApplyLayer(name string, layer []Point) *Pipeline {
+	return p.ApplyLayerWith(name, layer, "")
+}
+
+// ApplyLayerWith 는 보간 방식을 지정해 EQ 레이어를 더하는 단계를 추가합니다 (비어 있으면 로그-선형).
+func (p *Pipeline) ApplyLayerWith(name string, layer []Point, method InterpMethod) *Pipeline {
+	params := []Param{{Key: name + "EQ", Value: FormatPoints(layer)}}
+	if method != "" {
+		params = append(params, Param{Key: name + "Interpolation", Value: string(method)})
+	}
+	return p.Stage(Stage{
+		Name:   name,
+		Params: params,
+		Apply: func(c Curve) (Curve, float64) {
+			return ApplyLayerWith(c, layer, method), 0
+		},
+	})
+}
+
+// Loudness 는 ISO 226 등청감 보정 단계를 추가합니다 (기준 음량 -> 청취 음량, phon).
+func (p *Pipeline) Loudness(referencePhon, listeningPhon float64) *Pipeline {
+	return p.Stage(Stage{
+		Name: "loudness",
+		Params: []Param{
+			{Key: "loudnessRefPhon", Value: strconv.FormatFloat(referencePhon, 'f', -1, 64)},
+			{Key: "loudnessListenPhon", Value: strconv.FormatFloat(listeningPhon, 'f', -1, 64)},
+		},
+		Apply: func(c Curve) (Curve, float64) {
+			return ApplyLoudnessCompensation(c, referencePhon, listeningPhon), 0
+		},
+	})
+}
+
+// NormalizePreamp 는 최대 게인을 0 dB 로 맞추는 단계를 추가합니다.
+func (p *Pipeline) NormalizePreamp() *Pipeline {
+	return p.NormalizePreampWith(DefaultPreampOptions)
+}
+
+// NormalizePreampWith 는 Preamp 방식과 headroom 을 지정해 최대 게인을 맞추는 단계를 추가합니다.
+func (p *Pipeline) NormalizePreampWith(opts PreampOptions) *Pipeline {
+	return p.Stage(preampStage(opts))
+}
+
+// ReplacePreamp 는 모든 Preamp 단계의 옵션을 바꿉니다 (사용자 지정 Preamp 옵션용).
+func (p *Pipeline) ReplacePreamp(opts PreampOptions) *Pipeline {
+	for i, s := range p.stages {
+		if s.Name == preampStageName {
+			p.stages[i] = preampStage(opts)
+		}
+	}
+	return p
+}
+
+// Preamp 단계 이름
+const preampStageName = "noPreamp"
+
+// Preamp 단계 생성
+func preampStage(opts PreampOptions) Stage {
+	return Stage{
+		Name:   preampStageName,
+		Params: opts.Params(),
+		Apply: func(c Curve) (Curve, float64) {
+			return NormalizePreampWith(c, opts)
+		},
+	}
+}
+
+// Stages 는 파이프라인 단계 목록의 복사본을 반환합니다.
+func (p *Pipeline) Stages() []Stage {
+	return append([]Stage(nil), p.stages...)
+}
+
+// Params 는 단계 이름 목록과 각 단계 파라미터(중복 제거)를 반환합니다.
+func (p *Pipeline) Params() []Param {
+	names := make([]string, len(p.stages))
+	params := []Param{{Key: "pipeline"}}
+	seen := make(map[Param]bool)
+	for i, s := range p.stages {
+		names[i] = s.Name
+		for _, param := range s.Params {
+			if !seen[param] {
+				seen[param] = true
+				params = append(params, param)
+			}
+		}
+	}
+	params[0].Value = strings.Join(names, ",")
+	return params
+}
+
+// Run 은 소스 곡선에 모든 단계를 순서대로 적용합니다.
+func (p *Pipeline) Run(source Curve) Result {
+	result := Result{Curve: source.Clone(), Params: p.Params()}
+	for _, s := range p.stages {
+		var shift float64
+		result.Curve, shift = s.Apply(result.Curve)
+		result.PreampShift += shift
+	}
+	return result
+}
+
+// FormatPoints 는 포인트 목록을 "freq:gain,..." 형식의 문자열로 변환합니다.
+func FormatPoints(points []Point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = fmt.Sprintf("%s:%.1f", FormatFreq(p.Freq), p.Gain)
+	}
+	return strings.Join(parts, ",")
+}
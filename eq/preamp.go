@@ -0,0 +1,114 @@
+package eq
+
+import (
+	"math"
+	"strconv"
+)
+
+// PreampMode 는 클리핑 방지용 최대 게인 계산 방식입니다.
+type PreampMode string
+
+// 지원하는 Preamp 방식
+const (
+	PreampSampled      PreampMode = "sampled"      // GraphicEQ 포인트 중 최대 게인 (기존 방식)
+	PreampInterpolated PreampMode = "interpolated" // 포인트 사이를 촘촘히 보간한 응답의 최대 게인
+)
+
+// PreampModes 는 지원하는 Preamp 방식 목록입니다.
+var PreampModes = []PreampMode{PreampSampled, PreampInterpolated}
+
+// 보간 응답 최대값 계산 해상도 (옥타브당 포인트 수)
+const truePeakPointsPerOctave = 96
+
+// PreampOptions 는 Preamp 단계 옵션입니다.
+type PreampOptions struct {
+	Mode     PreampMode // 최대 게인 계산 방식 (비어 있으면 sampled)
+	Headroom float64    // 최대 게인을 0 dB 보다 이만큼 더 낮춤 (dB)
+}
+
+// DefaultPreampOptions 는 기존 출력과 동일한 기본 옵션입니다 (포인트 최대값을 0 dB 로).
+var DefaultPreampOptions = PreampOptions{Mode: PreampSampled}
+
+// ParsePreampMode 는 Preamp 방식 이름을 확인합니다 (비어 있으면 sampled).
+func ParsePreampMode(name string) (PreampMode, error) {
+	if name == "" {
+		return PreampSampled, nil
+	}
+	for _, m := range PreampModes {
+		if PreampMode(name) == m {
+			return m, nil
+		}
+	}
+	return "", errorf("알 수 없는 Preamp 방식 '%s' (%v 중 선택)", name, PreampModes)
+}
+
+// Validate 는 옵션 값을 확인합니다.
+func (opts PreampOptions) Validate() error {
+	if _, err := ParsePreampMode(string(opts.Mode)); err != nil {
+		return err
+	}
+	if opts.Headroom < 0 || opts.Headroom > 30 || isInvalid(opts.Headroom) {
+		return errorf("headroom 값이 올바르지 않습니다 (0~30 dB): %g", opts.Headroom)
+	}
+	return nil
+}
+
+// Params 는 기본값과 다른 옵션만 헤더 파라미터로 변환합니다.
+func (opts PreampOptions) Params() []Param {
+	var params []Param
+	if opts.Mode != "" && opts.Mode != PreampSampled {
+		params = append(params, Param{Key: "preampMode", Value: string(opts.Mode)})
+	}
+	if opts.Headroom > 0 {
+		params = append(params, Param{Key: "preampHeadroom", Value: strconv.FormatFloat(opts.Headroom, 'f', -1, 64)})
+	}
+	return params
+}
+
+// PeakGain 은 곡선의 최대 게인입니다.
+// interpolated 방식은 포인트 사이를 자연 3차 스플라인으로 촘촘히 보간해 플레이어의 보간 응답이 포인트보다 높아지는 경우까지 포함합니다.
+func PeakGain(c Curve, mode PreampMode) float64 {
+	peak := -math.MaxFloat64
+	for _, p := range c {
+		if !isInvalid(p.Gain) {
+			peak = math.Max(peak, p.Gain)
+		}
+	}
+	if mode != PreampInterpolated || len(c) < 3 {
+		return peak
+	}
+	it, err := NewInterpolator(c, InterpCubic)
+	if err != nil {
+		logf("경고: 보간 응답 최대값 계산 실패 (%v). 포인트 최대값을 사용합니다.\n", err)
+		return peak
+	}
+	for _, freq := range LogGrid(c[0].Freq, c[len(c)-1].Freq, truePeakPointsPerOctave) {
+		if gain := it.At(freq); !isInvalid(gain) {
+			peak = math.Max(peak, gain)
+		}
+	}
+	return peak
+}
+
+// NormalizePreampWith 는 최대 게인 (+ headroom) 이 0 dB 가 되도록 곡선 전체를 내리고, 이동량을 함께 반환합니다.
+// 이미 충분히 낮으면 이동하지 않습니다.
+func NormalizePreampWith(inputEQ Curve, opts PreampOptions) (Curve, float64) {
+	cleaned := make(Curve, len(inputEQ))
+	for i, p := range inputEQ {
+		if isInvalid(p.Gain) {
+			logf("경고: NormalizePreamp 입력에서 잘못된 게인 값 발견 (freq: %s). 0.0으로 처리.\n", FormatFreq(p.Freq))
+			p.Gain = 0.0
+		}
+		cleaned[i] = p
+	}
+	if len(cleaned) == 0 {
+		logf("NormalizePreamp 경고: 처리할 유효한 EQ 데이터가 없습니다.\n")
+		return cleaned, 0
+	}
+
+	shift := PeakGain(cleaned, opts.Mode) + opts.Headroom
+	if shift <= 1e-9 {
+		return cleaned, 0
+	}
+	return cleaned.Offset(-shift), shift
+}
@@ -0,0 +1,180 @@
+package eq
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version 은 출력 헤더에 기록되는 프로그램 버전입니다.
+const Version = "1.1.0"
+
+// 출력 헤더 키
+const (
+	headerGenerator   = "Generated by"
+	headerSource      = "Source"
+	headerDevice      = "Device"
+	headerMeasurement = "Measurement"
+	headerTarget      = "Target"
+	headerParam       = "Param"
+	headerPreampShift = "PreampShift"
+	headerChecksum    = "Checksum"
+)
+
+// Provenance 는 출력 파일의 출처 정보입니다.
+type Provenance struct {
+	Generator   string
+	Source      string
+	Device      DeviceInfo // 원본 경로/파일 이름에서 읽은 기기 정보 (선택)
+	Target      string
+	Params      []Param
+	PreampShift float64
+	Checksum    string
+}
+
+// FormatFile 은 출처 헤더 주석과 GraphicEQ 라인으로 된 출력 파일을 만듭니다.
+// opts.SeparatePreamp 이면 이동 전 곡선과 별도의 Preamp 라인 (-PreampShift) 을 씁니다.
+func FormatFile(eqData Curve, opts FormatOptions, prov Provenance) string {
+	if opts.SeparatePreamp {
+		eqData = eqData.Offset(prov.PreampShift)
+	}
+	body := Format(eqData, opts)
+	var sb strings.Builder
+	generator := prov.Generator
+	if generator == "" {
+		generator = "AHTVC " + Version
+	}
+	fmt.Fprintf(&sb, "# %s %s\n", headerGenerator, headerValue(generator))
+	fmt.Fprintf(&sb, "# %s: %s\n", headerSource, headerValue(prov.Source))
+	if name := prov.Device.Name(); name != "" {
+		fmt.Fprintf(&sb, "# %s: %s\n", headerDevice, headerValue(name))
+	}
+	if measurement := measurementHeader(prov.Device); measurement != "" {
+		fmt.Fprintf(&sb, "# %s: %s\n", headerMeasurement, headerValue(measurement))
+	}
+	fmt.Fprintf(&sb, "# %s: %s\n", headerTarget, headerValue(prov.Target))
+	params := append(append([]Param(nil), prov.Params...), opts.Params()...)
+	for _, p := range params {
+		fmt.Fprintf(&sb, "# %s: %s=%s\n", headerParam, headerValue(p.Key), headerValue(p.Value))
+	}
+	fmt.Fprintf(&sb, "# %s: %.2f\n", headerPreampShift, prov.PreampShift)
+	fmt.Fprintf(&sb, "# %s: sha256:%s\n", headerChecksum, Checksum(body))
+	if opts.SeparatePreamp {
+		// 반올림으로 감쇠가 부족해지지 않도록 0.01 dB 단위로 올림
+		preamp := -math.Ceil(prov.PreampShift*100) / 100
+		if preamp == 0 {
+			preamp = 0 // -0.00 출력 방지
+		}
+		fmt.Fprintf(&sb, "Preamp: %.2f dB\n", preamp)
+	}
+	sb.WriteString(body)
+	return sb.String()
+}
+
+// 헤더 값의 제어 문자 (줄바꿈 등) 를 공백으로 바꿔 헤더 줄이 추가되지 않도록 함
+func headerValue(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// 측정 정보 헤더 값 (예: source=crinacle, rig=711, channel=L)
+func measurementHeader(d DeviceInfo) string {
+	var fields []string
+	for _, f := range []struct{ key, value string }{{"source", d.Source}, {"rig", d.Rig}, {"channel", d.Channel}} {
+		if f.value != "" {
+			fields = append(fields, f.key+"="+f.value)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// Checksum 은 GraphicEQ 라인의 SHA-256 체크섬입니다.
+func Checksum(graphicEQLine string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(graphicEQLine)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseProvenance 는 출처 헤더 주석을 파싱합니다 (AHTVC 헤더가 없으면 found = false).
+func ParseProvenance(content string) (prov Provenance, found bool) {
+	var graphicEQLine string
+	for _, line := range splitLines(content) {
+		if strings.HasPrefix(line, "GraphicEQ:") {
+			graphicEQLine = line
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		comment := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		if strings.HasPrefix(comment, headerGenerator+" ") {
+			prov.Generator = strings.TrimSpace(strings.TrimPrefix(comment, headerGenerator))
+			found = true
+			continue
+		}
+		key, value, ok := strings.Cut(comment, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case headerSource:
+			prov.Source = value
+		case headerDevice:
+			device := ParseDeviceInfo(value)
+			prov.Device.Brand, prov.Device.Model, prov.Device.Variant = device.Brand, device.Model, device.Variant
+		case headerMeasurement:
+			for _, field := range strings.Split(value, ",") {
+				k, v, _ := strings.Cut(field, "=")
+				switch strings.TrimSpace(k) {
+				case "source":
+					prov.Device.Source = strings.TrimSpace(v)
+				case "rig":
+					prov.Device.Rig = strings.TrimSpace(v)
+				case "channel":
+					prov.Device.Channel = strings.TrimSpace(v)
+				}
+			}
+		case headerTarget:
+			prov.Target = value
+		case headerParam:
+			if k, v, ok := strings.Cut(value, "="); ok {
+				prov.Params = append(prov.Params, Param{Key: strings.TrimSpace(k), Value: strings.TrimSpace(v)})
+			}
+		case headerPreampShift:
+			if shift, err := strconv.ParseFloat(value, 64); err == nil {
+				prov.PreampShift = shift
+			}
+		case headerChecksum:
+			prov.Checksum = strings.TrimPrefix(value, "sha256:")
+		}
+	}
+
+	if found && prov.Checksum != "" && graphicEQLine != "" && prov.Checksum != Checksum(graphicEQLine) {
+		logf("경고: 헤더 체크섬이 GraphicEQ 데이터와 일치하지 않습니다 (파일이 수정되었을 수 있음).\n")
+	}
+	return prov, found
+}
+
+// DetectTarget 은 입력 파일의 타겟을 추정합니다 (AHTVC 헤더 > 파일 이름 순).
+// 다른 헤더 값처럼 언어와 무관한 영어 표기를 반환합니다.
+func DetectTarget(filename, content string) string {
+	if prov, found := ParseProvenance(content); found {
+		return fmt.Sprintf("VDSF (%s output)", prov.Generator)
+	}
+	lowerName := strings.ToLower(filename)
+	switch {
+	case strings.Contains(lowerName, "vdsf"):
+		return "VDSF"
+	case strings.Contains(lowerName, "harman"):
+		return "Harman"
+	}
+	return "Harman (assumed)"
+}
@@ -0,0 +1,30 @@
+package eq
+
+import (
+	"strings"
+	"testing"
+)
+
+// 파일 이름의 줄바꿈으로 헤더 줄이 추가되지 않아야 함
+func TestFormatFileSanitizesHeaders(t *testing.T) {
+	prov := Provenance{
+		Source: "evil.txt\n# Target: VDSF\r\nGraphicEQ: 20 99",
+		Device: DeviceInfo{Brand: "Brand\nX", Model: "Model", Channel: "L\n"},
+		Target: "Harman\n# Checksum: sha256:0",
+		Params: []Param{{Key: "a\nb", Value: "c\td"}},
+	}
+	content := FormatFile(Curve{{Freq: 20, Gain: 1}, {Freq: 1000, Gain: 0}}, DefaultFormatOptions, prov)
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for _, line := range lines[:len(lines)-1] {
+		if !strings.HasPrefix(line, "# ") || strings.ContainsAny(line, "\r\t") {
+			t.Errorf("헤더 줄이 올바르지 않음: %q", line)
+		}
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "GraphicEQ: 20 1.0") {
+		t.Errorf("마지막 줄이 GraphicEQ 데이터가 아님:\n%s", content)
+	}
+	got, found := ParseProvenance(content)
+	if !found || got.Source != "evil.txt # Target: VDSF  GraphicEQ: 20 99" || got.Target != "Harman # Checksum: sha256:0" {
+		t.Errorf("ParseProvenance() = %+v", got)
+	}
+}
@@ -0,0 +1,180 @@
+package eq
+
+import (
+	"math"
+)
+
+// 품질 지표 계산 대역 (Name 은 번역 카탈로그의 원문)
+var qualityBands = []struct {
+	Key, Name string
+	Low, High float64
+}{
+	{Key: "bass", Name: "저음 (20-250Hz)", Low: 20, High: 250},
+	{Key: "mid", Name: "중음 (250-4kHz)", Low: 250, High: 4000},
+	{Key: "treble", Name: "고음 (4k-20kHz)", Low: 4000, High: 20000},
+}
+
+// Olive-Welti IEM 선호도 모델 계산 범위
+const (
+	preferenceLowFreq  = 20.0
+	preferenceHighFreq = 10000.0
+)
+
+// BandDeviation 은 대역별 VDSF 타겟 편차입니다.
+// Key 는 언어와 무관한 대역 식별자 (bass, mid, treble) 이고, Name 은 화면에 표시할 때 번역합니다.
+type BandDeviation struct {
+	Key  string  `json:"key"`
+	Name string  `json:"name"`
+	RMS  float64 `json:"rms"`
+}
+
+// Metrics 는 결과 EQ 품질 지표입니다.
+type Metrics struct {
+	Bands        []BandDeviation `json:"bands"`
+	TotalRMS     float64         `json:"totalRms"`
+	Preference   float64         `json:"preferenceScore"`
+	MaxBoost     float64         `json:"maxBoost"`
+	DynamicRange float64         `json:"dynamicRange"`
+	Preamp       float64         `json:"preamp"`
+	UsedRaw      bool            `json:"usedRawMeasurement"`
+}
+
+// ComputeMetrics 는 품질 지표를 계산합니다.
+// curveEQ는 Preamp 적용 전 결과, idealEQ는 소스 EQ + Harman->VDSF 차이(VDSF 타겟을 정확히 맞추는 EQ)입니다.
+// raw 측정값이 주어지면 예상 응답(raw + curveEQ)과 VDSF 타겟(raw + idealEQ)을 raw 측정 범위 안에서 비교합니다.
+func ComputeMetrics(curveEQ, idealEQ Curve, raw []Point) Metrics {
+	metrics := Metrics{UsedRaw: len(raw) > 0}
+	// raw 측정값은 촘촘하므로 로그-선형 보간으로 충분 (보간 방식 설정은 타겟/레이어 단계에만 적용)
+	var rawAt *Interpolator
+	if metrics.UsedRaw {
+		var err error
+		if rawAt, err = NewInterpolator(raw, InterpLogLinear); err != nil {
+			logf("경고: raw 측정값을 사용할 수 없습니다 (%v).\n", err)
+			metrics.UsedRaw = false
+		}
+	}
+
+	var freqs []float64
+	var errs []float64
+	maxGain := -math.MaxFloat64
+	minGain := math.MaxFloat64
+	for _, p := range curveEQ {
+		freq, gain := p.Freq, p.Gain
+		if isInvalid(gain) {
+			continue
+		}
+		maxGain = math.Max(maxGain, gain)
+		minGain = math.Min(minGain, gain)
+
+		target, _ := idealEQ.At(freq)
+		predicted := gain
+		if metrics.UsedRaw {
+			if freq < raw[0].Freq || freq > raw[len(raw)-1].Freq {
+				continue
+			}
+			rawGain := rawAt.At(freq)
+			predicted += rawGain
+			target += rawGain
+		}
+		freqs = append(freqs, freq)
+		errs = append(errs, predicted-target)
+	}
+	if len(freqs) == 0 {
+		return metrics
+	}
+
+	metrics.MaxBoost = math.Max(maxGain, 0)
+	metrics.DynamicRange = maxGain - minGain
+	metrics.Preamp = -metrics.MaxBoost
+
+	// 레벨 차이는 Preamp로 보정되므로 평균 오차를 제거한 뒤 편차를 계산
+	errs = removeMean(errs)
+	metrics.TotalRMS = RMS(errs)
+	for _, band := range qualityBands {
+		var bandErrs []float64
+		for i, freq := range freqs {
+			if freq >= band.Low && freq < band.High {
+				bandErrs = append(bandErrs, errs[i])
+			}
+		}
+		metrics.Bands = append(metrics.Bands, BandDeviation{Key: band.Key, Name: band.Name, RMS: RMS(bandErrs)})
+	}
+	metrics.Preference = PreferenceScore(freqs, errs)
+	return metrics
+}
+
+// PreferenceScore 는 오차 곡선의 Olive-Welti IEM 예측 선호도 점수입니다.
+// PPR = 100.0795 - 8.5*SD - 6.796*|Slope| - 3.475*AAD (오차 곡선 20Hz~10kHz 기준)
+func PreferenceScore(freqs, errs []float64) float64 {
+	var logFreqs, bandErrs []float64
+	for i, freq := range freqs {
+		if freq >= preferenceLowFreq && freq <= preferenceHighFreq {
+			logFreqs = append(logFreqs, math.Log10(freq))
+			bandErrs = append(bandErrs, errs[i])
+		}
+	}
+	if len(bandErrs) < 2 {
+		return 0
+	}
+
+	mean := 0.0
+	for _, e := range bandErrs {
+		mean += e
+	}
+	mean /= float64(len(bandErrs))
+
+	sd, aad := 0.0, 0.0
+	for _, e := range bandErrs {
+		sd += (e - mean) * (e - mean)
+		aad += math.Abs(e - mean)
+	}
+	sd = math.Sqrt(sd / float64(len(bandErrs)))
+	aad /= float64(len(bandErrs))
+
+	// 로그 주파수에 대한 오차의 선형 회귀 기울기
+	meanX := 0.0
+	for _, x := range logFreqs {
+		meanX += x
+	}
+	meanX /= float64(len(logFreqs))
+	num, den := 0.0, 0.0
+	for i, x := range logFreqs {
+		num += (x - meanX) * (bandErrs[i] - mean)
+		den += (x - meanX) * (x - meanX)
+	}
+	slope := 0.0
+	if den > 1e-12 {
+		slope = num / den
+	}
+
+	return 100.0795 - 8.5*sd - 6.796*math.Abs(slope) - 3.475*aad
+}
+
+// 평균 제거
+func removeMean(values []float64) []float64 {
+	if len(values) == 0 {
+		return values
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = v - mean
+	}
+	return out
+}
+
+// RMS 는 값들의 제곱평균제곱근입니다.
+func RMS(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
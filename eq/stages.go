@@ -0,0 +1,147 @@
+package eq
+
+// ApplyTarget 은 소스 곡선에 타겟 차이 곡선(예: HarmanToVDSF)을 더합니다.
+// 두 곡선의 주파수를 합친 격자를 사용하며, 한쪽에 없는 포인트는 0 dB 로 취급합니다.
+func ApplyTarget(source, offset Curve) Curve {
+	result := make(Curve, 0, len(source)+len(offset))
+	i, j := 0, 0
+	for i < len(source) || j < len(offset) {
+		switch {
+		case j == len(offset) || (i < len(source) && source[i].Freq < offset[j].Freq):
+			result = append(result, source[i])
+			i++
+		case i == len(source) || offset[j].Freq < source[i].Freq:
+			result = append(result, offset[j])
+			j++
+		default:
+			result = append(result, Point{Freq: source[i].Freq, Gain: source[i].Gain + offset[j].Gain})
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Smooth 는 startFreq 이상 구간에 이동 평균 스무딩을 적용합니다 (가장자리는 원본 유지).
+func Smooth(inputEQ Curve, windowSize int, startFreq float64) Curve {
+	outputEQ := inputEQ.Clone()
+	sortedFreqs := inputEQ.Freqs()
+
+	if windowSize <= 1 || windowSize%2 == 0 {
+		logf("경고: 이동 평균 윈도우 크기(%d)는 1보다 큰 홀수여야 합니다. 스무딩을 건너뜁니다.\n", windowSize)
+		return outputEQ
+	}
+	if len(sortedFreqs) < windowSize {
+		logf("경고: 데이터 포인트 개수(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너뜁니다.\n", len(sortedFreqs), windowSize)
+		return outputEQ
+	}
+
+	halfWindow := windowSize / 2
+	startIndex := -1
+	for i, freq := range sortedFreqs {
+		if freq >= startFreq {
+			startIndex = i
+			break
+		}
+	}
+
+	if startIndex == -1 || len(sortedFreqs)-startIndex < windowSize {
+		if startIndex == -1 {
+			logf("%f Hz 이상 포인트를 찾지 못해 스무딩을 건너뜁니다.\n", startFreq)
+		} else {
+			logf("%f Hz 이상 데이터 포인트(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너뜁니다.\n", startFreq, len(sortedFreqs)-startIndex, windowSize)
+		}
+		return outputEQ
+	}
+
+	logf("%f Hz 이상 고음역대에 이동 평균 스무딩 적용 (Window=%d)...\n", startFreq, windowSize)
+
+	tempGains := make([]float64, len(sortedFreqs))
+	for i, p := range inputEQ {
+		tempGains[i] = p.Gain
+	}
+
+	// 이동 평균 계산 (가장자리 처리는 원본 유지 방식)
+	smoothedOutputGains := make([]float64, len(sortedFreqs))
+	copy(smoothedOutputGains, tempGains) // 원본으로 초기화
+
+	for i := startIndex + halfWindow; i < len(sortedFreqs)-halfWindow; i++ {
+		sum := 0.0
+		count := 0
+		for j := i - halfWindow; j <= i+halfWindow; j++ {
+			if j >= 0 && j < len(tempGains) {
+				if !isInvalid(tempGains[j]) {
+					sum += tempGains[j]
+					count++
+				} else {
+					logf("경고: 스무딩 계산 중 잘못된 값 발견 (index: %d). 건너뜁니다.\n", j)
+				}
+			}
+		}
+		if count > 0 {
+			avg := sum / float64(count)
+			if !isInvalid(avg) {
+				smoothedOutputGains[i] = avg // 계산된 평균값으로 업데이트
+			} else {
+				logf("경고: 스무딩 평균 계산 결과가 잘못됨 (index: %d). 원래 값 유지.\n", i)
+			}
+		} else {
+			logf("경고: 스무딩 윈도우 내 유효 값 없음 (index: %d). 원래 값 유지.\n", i)
+		}
+	}
+
+	// 스무딩된 결과를 곡선으로 변환
+	for i := range outputEQ {
+		outputEQ[i].Gain = smoothedOutputGains[i]
+	}
+
+	return outputEQ
+}
+
+// ApplyTargetResampled 는 타겟 차이 곡선을 소스 곡선의 주파수로 보간해 더합니다 (소스 주파수 격자 유지).
+func ApplyTargetResampled(source, offset Curve, method InterpMethod) (Curve, error) {
+	it, err := NewInterpolator(offset, method)
+	if err != nil {
+		return nil, err
+	}
+	result := source.Clone()
+	for i := range result {
+		result[i].Gain += it.At(result[i].Freq)
+	}
+	return result, nil
+}
+
+// ApplyLayer 는 정렬된 레이어 포인트(예: X2Layer)를 로그-선형 보간해 곡선의 각 포인트에 더합니다.
+// 레이어 범위 밖의 주파수에는 가장자리 게인을 더합니다.
+func ApplyLayer(baseEQ Curve, layer []Point) Curve {
+	return ApplyLayerWith(baseEQ, layer, InterpLogLinear)
+}
+
+// ApplyLayerWith 는 ApplyLayer 와 같지만 보간 방식을 지정합니다.
+func ApplyLayerWith(baseEQ Curve, layer []Point, method InterpMethod) Curve {
+	resultEQ := baseEQ.Clone()
+	if len(layer) == 0 {
+		return resultEQ
+	}
+	it, err := NewInterpolator(layer, method)
+	if err != nil {
+		logf("경고: 레이어 보간기 생성 실패 (%v). 레이어를 건너뜁니다.\n", err)
+		return resultEQ
+	}
+
+	for i, p := range baseEQ {
+		newGain := p.Gain + it.At(p.Freq)
+		if isInvalid(newGain) {
+			logf("경고: ApplyLayer 적용 중 잘못된 값 발생 (freq: %s). 원래 값 유지.\n", FormatFreq(p.Freq))
+			continue
+		}
+		resultEQ[i].Gain = newGain
+	}
+	return resultEQ
+}
+
+// NormalizePreamp 는 최대 게인이 0 dB 가 되도록 곡선 전체를 내리고, 적용된 이동량을 함께 반환합니다.
+// 최대 게인이 0 dB 이하이면 이동하지 않습니다.
+func NormalizePreamp(inputEQ Curve) (Curve, float64) {
+	return NormalizePreampWith(inputEQ, DefaultPreampOptions)
+}
@@ -0,0 +1,3 @@
+module ahtvc
+
+go 1.21
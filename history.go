@@ -0,0 +1,348 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 저장 파일 이름과 보관할 최대 변환 기록 수
+const (
+	historyFilename   = "history.json"
+	presetsFilename   = "presets.json"
+	maxHistoryEntries = 50
+)
+
+// 변환 기록과 프리셋에 저장하는 폼 필드 (파일 제외)
+var formParamFields = []string{
+	"pipeline", "precision", "quantizeStep", "minFreq", "maxFreq", "maxPoints",
+	"preampMode", "preampHeadroom", "separatePreamp", "levelMatch", "loudnessLevels", "loudnessRef",
+}
+
+// 변환 기록 하나 (다시 실행할 수 있도록 입력 파일 내용도 저장)
+type historyEntry struct {
+	ID             string            `json:"id"`
+	Time           time.Time         `json:"time"`
+	SourceFilename string            `json:"sourceFilename"`
+	Source         string            `json:"source"`
+	SourcePath     string            `json:"sourcePath,omitempty"` // 타겟/기기 정보 추정용 원본 경로
+	RawFilename    string            `json:"rawFilename,omitempty"`
+	Raw            string            `json:"raw,omitempty"`
+	Params         map[string]string `json:"params"`
+	Outputs        []historyOutput   `json:"outputs"`
+}
+
+// 변환 기록의 출력 파일
+type historyOutput struct {
+	Name     string `json:"name"`
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// 화면/JSON 응답용 변환 기록 요약 (파일 내용 제외)
+type historySummary struct {
+	ID             string    `json:"id"`
+	Time           time.Time `json:"time"`
+	SourceFilename string    `json:"sourceFilename"`
+	RawFilename    string    `json:"rawFilename,omitempty"`
+	Pipeline       string    `json:"pipeline,omitempty"`
+	Outputs        []string  `json:"outputs"`
+}
+
+// 이름이 있는 파라미터 묶음
+type preset struct {
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params"`
+}
+
+// 설정 폴더의 JSON 파일 기반 변환 기록/프리셋 저장소 (nil 이면 저장하지 않음)
+// 처음 읽은 뒤에는 메모리의 목록을 사용하고, 바뀔 때만 파일에 씁니다.
+type historyStore struct {
+	mu            sync.Mutex
+	dir           string
+	entries       []historyEntry // 최신 순
+	historyLoaded bool
+	presets       []preset // 이름 순
+	presetsLoaded bool
+}
+
+// 찾을 수 없는 변환 기록/프리셋/기기 오류 (웹 요청에서 404 로 응답)
+type notFoundError struct {
+	err error
+}
+
+func (e *notFoundError) Error() string { return e.err.Error() }
+func (e *notFoundError) Unwrap() error { return e.err }
+
+// 번역할 수 있는 찾을 수 없음 오류 생성
+func notFoundf(format string, args ...interface{}) error {
+	return &notFoundError{err: errorf(format, args...)}
+}
+
+// 찾을 수 없음 오류인지 확인
+func isNotFound(err error) bool {
+	var notFound *notFoundError
+	return errors.As(err, &notFound)
+}
+
+// 기본 저장 폴더 (사용자 설정 폴더의 ahtvc)
+func defaultDataDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "ahtvc"), nil
+}
+
+// 저장소 열기 (폴더가 없으면 생성)
+func openHistoryStore(dir string) (*historyStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &historyStore{dir: dir}, nil
+}
+
+// JSON 파일 읽기 (파일이 없으면 그대로 둠)
+func readJSONFile(path string, v interface{}) error {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+// JSON 파일 저장 (임시 파일에 쓴 뒤 이름 변경)
+func writeJSONFile(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// 변환 기록 읽기 (최신 순, 처음 한 번만 파일에서 읽음)
+func (s *historyStore) loadHistory() ([]historyEntry, error) {
+	if !s.historyLoaded {
+		var entries []historyEntry
+		if err := readJSONFile(filepath.Join(s.dir, historyFilename), &entries); err != nil {
+			return nil, err
+		}
+		s.entries, s.historyLoaded = entries, true
+	}
+	return s.entries, nil
+}
+
+// 변환 기록 추가 (오래된 기록은 maxHistoryEntries 개까지만 보관)
+func (s *historyStore) Add(entry historyEntry) (historyEntry, error) {
+	if s == nil {
+		return entry, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.loadHistory()
+	if err != nil {
+		return entry, err
+	}
+	entry.ID = newHistoryID(entry.Time)
+	entries = append([]historyEntry{entry}, entries...)
+	if len(entries) > maxHistoryEntries {
+		entries = entries[:maxHistoryEntries]
+	}
+	if err := writeJSONFile(filepath.Join(s.dir, historyFilename), entries); err != nil {
+		return entry, err
+	}
+	s.entries = entries
+	return entry, nil
+}
+
+// 변환 기록 요약 목록 (최신 순)
+func (s *historyStore) Summaries() ([]historySummary, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.loadHistory()
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]historySummary, len(entries))
+	for i, e := range entries {
+		summaries[i] = e.summary()
+	}
+	return summaries, nil
+}
+
+// 변환 기록 요약
+func (e historyEntry) summary() historySummary {
+	summary := historySummary{ID: e.ID, Time: e.Time, SourceFilename: e.SourceFilename, RawFilename: e.RawFilename, Pipeline: e.Params["pipeline"]}
+	for _, out := range e.Outputs {
+		summary.Outputs = append(summary.Outputs, out.Filename)
+	}
+	return summary
+}
+
+// ID 로 변환 기록 찾기
+func (s *historyStore) Entry(id string) (historyEntry, error) {
+	if s == nil {
+		return historyEntry{}, notFoundf("변환 기록 저장소를 사용할 수 없습니다")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.loadHistory()
+	if err != nil {
+		return historyEntry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return historyEntry{}, notFoundf("변환 기록을 찾을 수 없습니다: '%s'", id)
+}
+
+// 프리셋 읽기 (이름 순, 처음 한 번만 파일에서 읽음)
+func (s *historyStore) loadPresets() ([]preset, error) {
+	if !s.presetsLoaded {
+		var presets []preset
+		if err := readJSONFile(filepath.Join(s.dir, presetsFilename), &presets); err != nil {
+			return nil, err
+		}
+		s.presets, s.presetsLoaded = presets, true
+	}
+	return s.presets, nil
+}
+
+// 프리셋 목록
+func (s *historyStore) Presets() ([]preset, error) {
+	if s == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadPresets()
+}
+
+// 이름으로 프리셋 찾기
+func (s *historyStore) Preset(name string) (preset, error) {
+	presets, err := s.Presets()
+	if err != nil {
+		return preset{}, err
+	}
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return preset{}, notFoundf("프리셋을 찾을 수 없습니다: '%s'", name)
+}
+
+// 프리셋 저장 (같은 이름이 있으면 덮어씀)
+func (s *historyStore) SavePreset(p preset) error {
+	if s == nil {
+		return errorf("프리셋 저장소를 사용할 수 없습니다")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	loaded, err := s.loadPresets()
+	if err != nil {
+		return err
+	}
+	presets := append([]preset(nil), loaded...)
+	replaced := false
+	for i := range presets {
+		if presets[i].Name == p.Name {
+			presets[i], replaced = p, true
+		}
+	}
+	if !replaced {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	if err := writeJSONFile(filepath.Join(s.dir, presetsFilename), presets); err != nil {
+		return err
+	}
+	s.presets = presets
+	return nil
+}
+
+// 변환 기록 ID 생성 (시각 + 임의 값)
+func newHistoryID(t time.Time) string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("변환 기록 ID 생성 실패: %v", err))
+	}
+	return t.UTC().Format("20060102-150405") + "-" + hex.EncodeToString(buf)
+}
+
+// 요청 폼에서 저장할 파라미터 읽기 (빈 값 제외)
+func formParams(r *http.Request) map[string]string {
+	params := make(map[string]string)
+	for _, field := range formParamFields {
+		if value := strings.TrimSpace(r.FormValue(field)); value != "" {
+			params[field] = value
+		}
+	}
+	return params
+}
+
+// 화면/JSON 응답에 변환 기록과 프리셋 목록 추가
+func addHistoryData(resultData map[string]interface{}) {
+	resultData["HistoryEnabled"] = activeHistory != nil
+	summaries, err := activeHistory.Summaries()
+	if err != nil {
+		logPrintf("변환 기록 읽기 오류: %v", err)
+	}
+	presets, err := activeHistory.Presets()
+	if err != nil {
+		logPrintf("프리셋 읽기 오류: %v", err)
+	}
+	resultData["History"] = summaries
+	resultData["Presets"] = presets
+}
+
+// ?history=ID 또는 ?preset=이름 으로 폼 값 채우기
+func prefillForm(r *http.Request, resultData map[string]interface{}) error {
+	var params map[string]string
+	if id := r.URL.Query().Get("history"); id != "" {
+		entry, err := activeHistory.Entry(id)
+		if err != nil {
+			return err
+		}
+		params = entry.Params
+		resultData["Rerun"] = entry.summary()
+	} else if name := r.URL.Query().Get("preset"); name != "" {
+		p, err := activeHistory.Preset(name)
+		if err != nil {
+			return err
+		}
+		params = p.Params
+	}
+	if params == nil {
+		return nil
+	}
+	resultData["Form"] = params
+	if pipeline := params["pipeline"]; pipeline != "" {
+		resultData["SelectedPipeline"] = pipeline
+	}
+	return nil
+}
+
+// 시작 시 연 변환 기록 저장소 (nil 이면 기록하지 않음)
+var activeHistory *historyStore
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 임시 폴더의 변환 기록 저장소 사용
+func useTempHistory(t *testing.T) *historyStore {
+	t.Helper()
+	store, err := openHistoryStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	activeHistory = store
+	t.Cleanup(func() { activeHistory = nil })
+	return store
+}
+
+func TestHistoryRecordAndRerun(t *testing.T) {
+	store := useTempHistory(t)
+	fields := map[string]string{csrfFieldName: testCSRFToken, "precision": "2", "levelMatch": "1", "presetName": "정밀"}
+	req := newUploadRequest(t, "/", map[string][]byte{"sourceHarmanFile": readCoreEQ(t)}, fields, testCSRFToken)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+
+	summaries, err := store.Summaries()
+	if err != nil || len(summaries) != 1 {
+		t.Fatalf("변환 기록 = %v (err %v), want 1개", summaries, err)
+	}
+	entry, err := store.Entry(summaries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.SourceFilename != "sourceHarmanFile.txt" || entry.Source == "" || len(entry.Outputs) != 2 {
+		t.Errorf("저장된 기록이 올바르지 않음: %s, 출력 %d개", entry.SourceFilename, len(entry.Outputs))
+	}
+	if entry.Params["precision"] != "2" || entry.Params["levelMatch"] != "1" {
+		t.Errorf("저장된 파라미터 = %v", entry.Params)
+	}
+	if p, err := store.Preset("정밀"); err != nil || p.Params["precision"] != "2" {
+		t.Errorf("프리셋 = %v (err %v)", p, err)
+	}
+
+	// 기록 페이지는 저장된 파라미터로 폼을 채움
+	rec = httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?history="+entry.ID, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `name="historyID" value="`+entry.ID+`"`) {
+		t.Fatalf("다시 실행 폼이 없음 (status %d)", rec.Code)
+	}
+
+	// 파일 없이 새 파라미터로 다시 실행
+	fields = map[string]string{csrfFieldName: testCSRFToken, "historyID": entry.ID, "precision": "0"}
+	req = newUploadRequest(t, "/", nil, fields, testCSRFToken)
+	rec = httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "GraphicEQ:") {
+		t.Fatalf("다시 실행 실패: status %d\n%s", rec.Code, rec.Body.String())
+	}
+	if summaries, _ := store.Summaries(); len(summaries) != 2 {
+		t.Errorf("변환 기록 %d개, want 2개", len(summaries))
+	}
+}
+
+func TestHistoryUnknownEntry(t *testing.T) {
+	useTempHistory(t)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?history=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+// 변환 기록이 꺼져 있으면 기록 요청은 404
+func TestHistoryDisabled(t *testing.T) {
+	activeHistory = nil
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?history=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+}
+
+// 저장소를 읽을 수 없는 오류는 404 가 아니라 500 으로 응답
+func TestHistoryStoreErrorStatus(t *testing.T) {
+	silenceLogs(t)
+	store := useTempHistory(t)
+	if err := os.WriteFile(filepath.Join(store.dir, historyFilename), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?history=missing", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+}
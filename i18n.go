@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ahtvc/eq"
+)
+
+// 웹 UI 언어
+type language string
+
+const (
+	langKorean  language = "ko" // 기본 언어 (원문)
+	langEnglish language = "en"
+
+	langCookieName = "ahtvc_lang"
+)
+
+// 지원 언어 (첫 번째가 기본값)
+var supportedLangs = []language{langKorean, langEnglish}
+
+// 번역 카탈로그 (한국어 원문 -> 번역). 템플릿 문구, 웹 오류 메시지, 파서 진단 메시지를 포함합니다.
+var catalogs = map[language]map[string]string{
+	langEnglish: {
+		// 공통 템플릿
+		"이어폰/헤드폰의 Harman 타겟 AutoEQ 파일을 업로드하세요.":        "Upload the Harman-target AutoEQ file of your earphones/headphones.",
+		"자동으로 VDSF 타겟 기반의 EQ 파일을 생성합니다 (For Wavelet).": "A VDSF-target EQ file is generated automatically (for Wavelet).",
+		"EQ 파일 비교":            "Compare EQ files",
+		"AutoEQ 저장소에서 기기 검색:": "Search devices in the AutoEQ repository:",
+		"예: HD 650":           "e.g. HD 650",
+		"검색":                  "Search",
+		"'%s' 와 일치하는 기기가 없습니다.":                                                      "No devices match '%s'.",
+		"Harman 타겟 EQ 파일 (GraphicEQ/Equalizer APO .txt, AutoEQ .csv 또는 설정 폴더 .zip):": "Harman-target EQ file (GraphicEQ/Equalizer APO .txt, AutoEQ .csv or config folder .zip):",
+		"AutoEQ 저장소 기기:":                                                             "AutoEQ repository device:",
+		"파일을 선택하지 않으면 저장소 파일을 사용합니다":                                                 "the repository file is used if no file is selected",
+		"다시 실행:": "Rerun:",
+		"측정값":    "measurement",
+		"파일을 선택하지 않으면 저장된 입력 파일을 사용합니다": "the saved input files are used if no file is selected",
+		"변환 파이프라인:": "Conversion pipeline:",
+		"Raw 측정값 파일 (선택, AutoEQ CSV / squig.link / REW 내보내기):": "Raw measurement file (optional, AutoEQ CSV / squig.link / REW export):",
+		"출력 옵션":                                  "Output options",
+		"소수점 자리수 (0~3):":                         "Decimal places (0-3):",
+		"게인 양자화 단위 (dB, 예: 0.5):":                "Gain quantization step (dB, e.g. 0.5):",
+		"사용 안 함":                                 "Disabled",
+		"최소 주파수 (Hz):":                           "Minimum frequency (Hz):",
+		"최대 주파수 (Hz):":                           "Maximum frequency (Hz):",
+		"제한 없음":                                  "No limit",
+		"최대 포인트 수 (예: 127):":                     "Maximum number of points (e.g. 127):",
+		"Preamp 계산 방식:":                          "Preamp calculation:",
+		"파이프라인 설정":                               "Pipeline default",
+		"포인트 최대값 (sampled)":                      "Maximum of points (sampled)",
+		"보간 응답 최대값 (interpolated)":               "Maximum of interpolated response (interpolated)",
+		"추가 여유 (dB, 예: 1):":                      "Extra headroom (dB, e.g. 1):",
+		"게인을 내리는 대신 별도 Preamp 라인으로 출력":           "Write a separate Preamp line instead of lowering the gains",
+		"결과 간 체감 음량 맞춤 (ITU-R 468, A/B 비교용)":     "Match perceived loudness across results (ITU-R 468, for A/B comparison)",
+		"등청감 보정 청취 음량 (phon, 쉼표로 구분, 예: 60,40):": "Loudness compensation listening levels (phon, comma separated, e.g. 60,40):",
+		"기준 음량 (phon, 20~90):":                   "Reference level (phon, 20-90):",
+		"현재 설정을 프리셋으로 저장 (선택, 이름):":              "Save current settings as a preset (optional, name):",
+		"저장 안 함":                                 "Do not save",
+		"변환하기":                                   "Convert",
+		"프리셋 저장됨:":                               "Preset saved:",
+		"감지된 입력 형식:":                             "Detected input format:",
+		"음량 맞춤 오프셋: %+.2f dB":                    "Level-matching offset: %+.2f dB",
+		"클립보드 복사":                                "Copy to clipboard",
+		"복사됨!":                                   "Copied!",
+		"파일로 저장 (.txt)":                          "Save as file (.txt)",
+		"클립보드 복사에 실패했습니다.":                       "Failed to copy to the clipboard.",
+		"클립보드 API를 사용할 수 없습니다.":                  "The clipboard API is not available.",
+		"파일 다운로드 오류: 타입 오류":                      "File download error: type error",
+		"파일 다운로드 오류: 데이터 없음":                     "File download error: no data",
+		"오류:":         "Error:",
+		"저장된 프리셋":     "Saved presets",
+		"변환 기록":       "Conversion history",
+		"시각":          "Time",
+		"입력 파일":       "Input file",
+		"파이프라인":       "Pipeline",
+		"출력":          "Outputs",
+		"%d개":         "%d",
+		"다시 실행":       "Rerun",
+		"플레이어별 내보내기:": "Player exports:",
+		"품질 지표":       "Quality metrics",
+		"Raw 측정값 기준":  "based on raw measurement",
+		"예측 선호도 점수 (Olive-Welti IEM)": "Predicted preference score (Olive-Welti IEM)",
+		"VDSF 타겟 RMS 편차 (전체)":         "RMS deviation from VDSF target (overall)",
+		"VDSF 타겟 RMS 편차":              "RMS deviation from VDSF target",
+		"저음 (20-250Hz)":               "Bass (20-250Hz)",
+		"중음 (250-4kHz)":               "Mids (250-4kHz)",
+		"고음 (4k-20kHz)":               "Treble (4k-20kHz)",
+		"최대 부스트":                      "Maximum boost",
+		"전체 다이내믹 레인지":                 "Overall dynamic range",
+		"필요 Preamp":                   "Required preamp",
+		"Harman -> VDSF (기본)":         "Harman -> VDSF (default)",
+		"Harman -> VDSF, 고음역대 스무딩 강화 (타겟을 PCHIP 보간으로 입력 주파수에 맞춤)": "Harman -> VDSF, stronger treble smoothing (target resampled to the input frequencies with PCHIP)",
+
+		// 비교 페이지
+		"EQ 비교": "EQ comparison",
+		"두 GraphicEQ 파일(예: 이전 AHTVC 결과와 새 결과, Result 1과 Result 2)을 공통 로그 격자에서 비교합니다.": "Compares two GraphicEQ files (e.g. an older and a newer AHTVC result, Result 1 and Result 2) on a common log grid.",
+		"변환 페이지로":           "Back to the converter",
+		"파일 A (.txt/.csv):": "File A (.txt/.csv):",
+		"파일 B (.txt/.csv):": "File B (.txt/.csv):",
+		"비교하기":              "Compare",
+		"최대 차이 (B - A)":     "Maximum difference (B - A)",
+		"RMS 차이":            "RMS difference",
+		"평균 차이":             "Mean difference",
+		"주파수 (Hz)":          "Frequency (Hz)",
+
+		// 웹 오류 메시지
+		"업로드 거부: %v":           "Upload rejected: %v",
+		"변환 기록 오류: %v":         "History error: %v",
+		"파일 읽기 오류: %v":         "File read error: %v",
+		"기기 파일 읽기 오류 (%s): %v": "Device file read error (%s): %v",
+		"파일 업로드 오류: %v":        "File upload error: %v",
+		"입력 파일 파싱 오류: %v\n입력 파일 내용을 확인해주세요.":           "Input file parse error: %v\nPlease check the contents of the input file.",
+		"알 수 없는 파이프라인: '%s'":                           "Unknown pipeline: '%s'",
+		"출력 옵션 오류: %v":                                 "Output option error: %v",
+		"Preamp 옵션 오류: %v":                             "Preamp option error: %v",
+		"등청감 보정 옵션 오류: %v":                             "Loudness compensation option error: %v",
+		"측정값 파일 읽기 오류: %v":                             "Measurement file read error: %v",
+		"측정값 파일 업로드 오류: %v":                            "Measurement file upload error: %v",
+		"측정값 파일 파싱 오류: %v":                             "Measurement file parse error: %v",
+		"파이프라인 구성 오류: %v":                              "Pipeline configuration error: %v",
+		"프리셋 저장 오류: %v":                                "Preset save error: %v",
+		"파일 업로드 오류 (%s): %v":                           "File upload error (%s): %v",
+		"파일 읽기 오류 (%s): %v":                            "File read error (%s): %v",
+		"입력 파일 파싱 오류 (%s): %v":                         "Input file parse error (%s): %v",
+		"AutoEQ 저장소가 설정되지 않았습니다 (-autoeq)":             "The AutoEQ repository is not configured (-autoeq)",
+		"AutoEQ 기기를 찾을 수 없습니다: '%s'":                   "AutoEQ device not found: '%s'",
+		"변환 기록 저장소를 사용할 수 없습니다":                        "The history store is not available",
+		"변환 기록을 찾을 수 없습니다: '%s'":                       "History entry not found: '%s'",
+		"프리셋을 찾을 수 없습니다: '%s'":                         "Preset not found: '%s'",
+		"프리셋 저장소를 사용할 수 없습니다":                          "The preset store is not available",
+		"'%s' 값이 올바르지 않습니다 (%d~%d 정수): '%s'":           "Invalid '%s' value (integer %d-%d): '%s'",
+		"'quantizeStep' 값이 올바르지 않습니다 (0~6 dB): '%s'":   "Invalid 'quantizeStep' value (0-6 dB): '%s'",
+		"주파수 범위가 올바르지 않습니다 (minFreq %d >= maxFreq %d)": "Invalid frequency range (minFreq %d >= maxFreq %d)",
+		"'maxPoints'는 0(제한 없음) 또는 2 이상이어야 합니다":         "'maxPoints' must be 0 (no limit) or at least 2",
+		"'preampHeadroom' 값이 올바르지 않습니다: '%s'":          "Invalid 'preampHeadroom' value: '%s'",
+		"기준 음량 값이 올바르지 않습니다: '%s'":                     "Invalid reference level: '%s'",
+		"청취 음량 값이 올바르지 않습니다: '%s'":                     "Invalid listening level: '%s'",
+		"청취 음량 목록이 비어 있습니다: '%s'":                      "The listening level list is empty: '%s'",
+		"바이너리 파일은 업로드할 수 없습니다 (AutoEQ 텍스트 파일을 선택하세요)":  "Binary files cannot be uploaded (choose an AutoEQ text file)",
+		"UTF-8 텍스트 파일만 지원합니다 (파일 인코딩을 UTF-8로 저장해주세요)":  "Only UTF-8 text files are supported (save the file as UTF-8)",
+		"보안 토큰이 없거나 일치하지 않습니다. 페이지를 새로고침한 뒤 다시 시도해주세요": "The security token is missing or does not match. Reload the page and try again",
+		"업로드 크기 제한(%dMB)을 초과했습니다":                      "The upload size limit (%dMB) was exceeded",
+		"ZIP 파일을 열 수 없습니다: %w":                         "Cannot open the ZIP file: %w",
+		"ZIP 안에서 주 설정 파일(config.txt)을 찾을 수 없습니다":       "No main config file (config.txt) found in the ZIP",
+		"요청 형식 오류: %w":                                 "Malformed request: %w",
+
+		// 실시간 미리보기
+		"실시간 미리보기": "Live preview",
+		"출력 옵션이나 파이프라인을 바꾸면 파일을 다시 올리지 않고 결과와 그래프를 갱신합니다. 플레이어별 내보내기와 품질 지표는 변환하기를 눌러야 갱신됩니다.": "Changing the output options or pipeline updates the results and graph without re-uploading the file. Player exports and quality metrics are updated when you press Convert.",
+		"출력 개수가 바뀌었습니다. 변환하기를 눌러 전체 결과를 다시 만드세요.":                                              "The number of outputs changed. Press Convert to rebuild all results.",
+		"미리보기 계산 중...": "Updating preview...",
+		"미리보기 갱신됨":     "Preview updated",
+		"미리보기 세션이 만료되었습니다. 파일을 다시 변환해주세요": "The preview session has expired. Convert the file again",
+		"POST 요청만 지원합니다": "Only POST requests are supported",
+
+		// 파이프라인 정의 파일 (eq 패키지)
+		"파이프라인 정의 파싱 오류: %w":                            "Pipeline definition parse error: %w",
+		"정의된 파이프라인이 없습니다":                               "No pipelines are defined",
+		"파이프라인 이름이 비어 있습니다":                             "A pipeline name is empty",
+		"파이프라인 이름 중복: '%s'":                             "Duplicate pipeline name: '%s'",
+		"파이프라인 '%s': 출력이 없습니다":                          "Pipeline '%s': no outputs",
+		"파이프라인 '%s': %w":                                "Pipeline '%s': %w",
+		"출력 이름이 비어 있습니다":                                "An output name is empty",
+		"출력 '%s': 단계가 없습니다":                             "Output '%s': no stages",
+		"출력 '%s' 단계 %d (%s): %w":                        "Output '%s' stage %d (%s): %w",
+		"interpolation 은 target/layer 단계에서만 사용할 수 있습니다": "interpolation can only be used in target/layer stages",
+		"points 가 올바르지 않습니다: %w":                        "Invalid points: %w",
+		"알 수 없는 타겟 '%s' (내장 '%s' 또는 points 사용)":         "Unknown target '%s' (use the built-in '%s' or points)",
+		"window 는 1보다 큰 홀수여야 합니다: %d":                   "window must be an odd number greater than 1: %d",
+		"startFreq 가 올바르지 않습니다: %f":                     "Invalid startFreq: %f",
+		"layer 단계에는 name 과 points 가 필요합니다":              "A layer stage needs name and points",
+		"refPhon 이 올바르지 않습니다: %w":                       "Invalid refPhon: %w",
+		"listenPhon 이 올바르지 않습니다: %w":                    "Invalid listenPhon: %w",
+		"알 수 없는 단계 종류 '%s'":                             "Unknown stage type '%s'",
+
+		// 파서 진단 메시지 (eq 패키지)
+		"line %d: GraphicEQ 라인에서 유효한 포인트를 찾을 수 없음: '%s'":             "line %d: no valid points found in the GraphicEQ line: '%s'",
+		"'GraphicEQ:' 또는 'Filter:' 라인을 찾을 수 없음 (파일 형식을 확인하세요)":       "No 'GraphicEQ:' or 'Filter:' line found (check the file format)",
+		"line %d: Include 파일을 불러올 수 없음 ('%s'): 설정 폴더를 ZIP 으로 업로드하세요": "line %d: cannot load the Include file ('%s'): upload the config folder as a ZIP",
+		"Include 중첩이 너무 깊습니다 (%d단계 초과): '%s'":                        "Includes are nested too deeply (more than %d levels): '%s'",
+		"순환 Include: '%s'":                  "Circular Include: '%s'",
+		"Include 파일을 열 수 없음 ('%s'): %w":     "Cannot open the Include file ('%s'): %w",
+		"Include 파일 읽기 오류 ('%s'): %w":       "Include file read error ('%s'): %w",
+		"Include 를 펼친 설정이 너무 큽니다 (%dMB 초과)": "The config with Includes expanded is too large (over %dMB)",
+		"Include 경로가 설정 폴더 밖을 가리킵니다: '%s'":  "The Include path points outside the config folder: '%s'",
+		"파싱된 유효한 EQ 데이터 포인트가 없음":            "No valid EQ data points were parsed",
+		"%s 형식 파싱 오류: %w":                   "%s format parse error: %w",
+		"측정값 %s 형식 파싱 오류: %w":               "Measurement %s format parse error: %w",
+		"GraphicEQ 파일은 측정값으로 사용할 수 없습니다 (주파수 응답 파일을 선택하세요)":                      "A GraphicEQ file cannot be used as a measurement (choose a frequency response file)",
+		"line %d: 사용할 수 있는 열(%s)이 없음: '%s'":                                      "line %d: no usable column (%s): '%s'",
+		"인식할 수 없는 파일 형식입니다 (GraphicEQ, AutoEQ CSV, squig.link, REW 내보내기를 지원합니다)": "Unrecognized file format (GraphicEQ, AutoEQ CSV, squig.link and REW exports are supported)",
+		"유효한 데이터 포인트가 2개 미만입니다":                                                  "Fewer than 2 valid data points",
+		"포인트 주파수는 0보다 크고 오름차순이어야 합니다 (인덱스 %d: %s Hz)":                            "Point frequencies must be positive and ascending (index %d: %s Hz)",
+		"알 수 없는 보간 방식 '%s' (%v 중 선택)":                                            "Unknown interpolation method '%s' (choose one of %v)",
+		"보간할 포인트가 없습니다":                                                          "No points to interpolate",
+		"알 수 없는 Preamp 방식 '%s' (%v 중 선택)":                                        "Unknown preamp mode '%s' (choose one of %v)",
+		"headroom 값이 올바르지 않습니다 (0~30 dB): %g":                                    "Invalid headroom value (0-30 dB): %g",
+		"음량 %g phon 이 ISO 226 유효 범위(%g~%g phon)를 벗어났습니다":                         "Level %g phon is outside the ISO 226 valid range (%g-%g phon)",
+		"비교할 EQ 데이터가 없습니다":                                                       "No EQ data to compare",
+		"두 파일의 주파수 범위가 겹치지 않습니다 (A: %g-%g Hz, B: %g-%g Hz)":                      "The frequency ranges of the two files do not overlap (A: %g-%g Hz, B: %g-%g Hz)",
+
+		// 서버/폴더 감시 로그
+		"%s 에서 서버 시작...":            "Starting server on %s...",
+		"'%s' 음량 맞춤: %+.2f dB":      "'%s' level match: %+.2f dB",
+		"'%s' 출력 생성됨 (%s).":         "'%s' output generated (%s).",
+		"AutoEQ 기기 %d개 색인됨: %s":     "Indexed %d AutoEQ devices: %s",
+		"AutoEQ 저장소 색인 오류 (%s): %v": "AutoEQ repository index error (%s): %v",
+		"JSON 응답 오류: %v":            "JSON response error: %v",
+		"건너뜀 (%s): 이미 %s 출력 파일입니다":  "Skipped (%s): already a %s output file",
+		"경고: 변환 기록 저장소를 열 수 없습니다 (%v). 기록 없이 실행합니다.":                   "Warning: cannot open the history store (%v). Running without history.",
+		"경고: 입력 파일은 EQ가 아닌 주파수 응답 측정값으로 보입니다 (%s). 결과가 의도와 다를 수 있습니다.": "Warning: the input looks like a frequency response measurement, not an EQ (%s). The result may not be what you expect.",
+		"경고: 입력 파일은 이미 %s 출력입니다 (원본: %s). VDSF 변환이 중복 적용됩니다.":          "Warning: the input is already %s output (source: %s). The VDSF conversion will be applied twice.",
+		"미리보기 JSON 오류: %v":                       "Preview JSON error: %v",
+		"변환 기록 읽기 오류: %v":                        "History read error: %v",
+		"변환 기록 저장 오류: %v":                        "History save error: %v",
+		"변환 기록 저장 위치: %s":                        "History location: %s",
+		"변환 실패: %v":                              "Conversion failed: %v",
+		"AHTVC 출력 파일":                            "AHTVC output file",
+		"변환 실패 (%s): %v":                         "Conversion failed (%s): %v",
+		"변환 완료 (%s, %s): %s":                     "Converted (%s, %s): %s",
+		"브라우저 열기 오류: %v":                         "Browser open error: %v",
+		"비교 템플릿 실행 오류: %v":                       "Compare template error: %v",
+		"서버 시작 실패: %v":                           "Server start failed: %v",
+		"서버 종료 오류: %v":                           "Server shutdown error: %v",
+		"서버 종료.":                                 "Server stopped.",
+		"서버 주소: %s":                              "Server address: %s",
+		"설정 오류: %v":                              "Configuration error: %v",
+		"웹 브라우저 여는 중...":                         "Opening web browser...",
+		"입력 형식: %s":                              "Input format: %s",
+		"종료 신호 수신, 서버 종료 중...":                   "Shutdown signal received, stopping server...",
+		"템플릿 실행 오류: %v":                          "Template error: %v",
+		"파이프라인 %d개 로드됨: %s":                      "Loaded %d pipelines: %s",
+		"파이프라인 정의 파일 오류 (%s): %v":                "Pipeline definition file error (%s): %v",
+		"포트 열기 실패: %v":                           "Failed to open port: %v",
+		"폴더 감시 시작: %s -> %s (파이프라인 '%s', 주기 %v)": "Watching folder: %s -> %s (pipeline '%s', interval %v)",
+		"폴더 감시 종료":                               "Folder watch stopped",
+		"폴더 확인 오류: %v":                           "Folder scan error: %v",
+		"폼 채우기 오류: %v":                           "Form prefill error: %v",
+		"프리셋 읽기 오류: %v":                          "Preset read error: %v",
+
+		// CLI 출력 (compare, autoeq, watch) 과 서버 설정 오류
+		"%s [%s] 변환 완료: %s": "%s [%s] converted: %s",
+		"'%s' 와 일치하는 기기가 %d개입니다. 검색어를 더 구체적으로 하거나 -pick 번호를 지정하세요.": "%[2]d devices match '%[1]s'. Use a more specific search or pass -pick.",
+		"'%s' 와 일치하는 기기가 없습니다 (색인된 기기 %d개)":                         "No device matches '%s' (%d devices indexed)",
+		"-pick 번호가 올바르지 않습니다 (1~%d): %d":                            "Invalid -pick number (1-%d): %d",
+		"... 외 %d개":            "... and %d more",
+		"AutoEQ 저장소 색인 오류: %v": "AutoEQ repository index error: %v",
+		"변환 오류: %v":            "Conversion error: %v",
+		"사용법: ahtvc autoeq -repo DIR [-out DIR] [-pick N] [-pipeline name] [-pipelines file] [-exports] 검색어": "Usage: ahtvc autoeq -repo DIR [-out DIR] [-pick N] [-pipeline name] [-pipelines file] [-exports] query",
+		"저장 오류: %v":       "Save error: %v",
+		"출력 폴더 생성 오류: %v": "Output folder error: %v",
+		"RMS 차이: %.2f dB": "RMS difference: %.2f dB",
+		"SVG 저장 오류: %v":   "SVG save error: %v",
+		"그래프 저장됨: %s":     "Graph saved: %s",
+		"비교 오류: %v":       "Compare error: %v",
+		"사용법: ahtvc compare [-svg plot.svg] [-ppo 12] A.txt B.txt": "Usage: ahtvc compare [-svg plot.svg] [-ppo 12] A.txt B.txt",
+		"옥타브당 포인트 수가 올바르지 않습니다: %d":                                "Invalid points per octave: %d",
+		"최대 차이: %+.2f dB @ %.0f Hz":                                "Max difference: %+.2f dB @ %.0f Hz",
+		"평균 차이: %+.2f dB":                                          "Mean difference: %+.2f dB",
+		"'%s %s' 실행 오류: %w":                                        "'%s %s' failed: %w",
+		"지원되는 브라우저 열기 명령을 찾을 수 없음":                                 "No supported command to open a browser was found",
+		"페이지 렌더링 오류":                                               "Page rendering error",
+		"%s 값이 올바르지 않습니다: '%s'":                                    "Invalid %s value: '%s'",
+		"알 수 없는 인자: %s":                                            "Unknown arguments: %s",
+		"포트 번호가 올바르지 않습니다: %d":                                     "Invalid port number: %d",
+		"감시 상태 파일 오류 (%s): %v":                                     "Watch state file error (%s): %v",
+		"사용법: ahtvc watch -in DIR -out DIR [-interval 5s] [-pipeline name] [-pipelines file] [-exports] [-once] [-preamp-mode interpolated] [-headroom 1] [-separate-preamp] [-level-match] [-loudness-ref 80] [-loudness-levels 60,40]": "Usage: ahtvc watch -in DIR -out DIR [-interval 5s] [-pipeline name] [-pipelines file] [-exports] [-once] [-preamp-mode interpolated] [-headroom 1] [-separate-preamp] [-level-match] [-loudness-ref 80] [-loudness-levels 60,40]",
+		"파이프라인 정의 파일 오류 (%s): %w": "Pipeline definition file error (%s): %w",
+		"확인 주기가 올바르지 않습니다: %v":    "Invalid interval: %v",
+
+		// 파서/파이프라인 진단 메시지 (eq 패키지 경고)
+		"%f Hz 이상 고음역대에 이동 평균 스무딩 적용 (Window=%d)...":                 "Applying moving-average smoothing above %f Hz (Window=%d)...",
+		"%f Hz 이상 데이터 포인트(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너뜁니다.":          "Skipping smoothing: %f Hz and above has fewer data points (%d) than the window size (%d).",
+		"%f Hz 이상 포인트를 찾지 못해 스무딩을 건너뜁니다.":                            "Skipping smoothing: no points at or above %f Hz.",
+		"NormalizePreamp 경고: 처리할 유효한 EQ 데이터가 없습니다.":                  "NormalizePreamp warning: no valid EQ data to process.",
+		"경고: %s 게인 범위(%.0f~%.0f dB)를 벗어난 포인트 %d개를 제한했습니다.":           "Warning: %s: clamped points outside the gain range (%.0f to %.0f dB): %d",
+		"내보낼 EQ 데이터가 없습니다":                                           "No EQ data to export",
+		"지원하지 않는 내보내기 형식: %s":                                        "Unsupported export format: %s",
+		"경고: %s 내보내기 실패: %v":                                         "Warning: %s export failed: %v",
+		"경고: ApplyLayer 적용 중 잘못된 값 발생 (freq: %s). 원래 값 유지.":          "Warning: invalid value in ApplyLayer (freq: %s). Keeping the original value.",
+		"경고: Line %d, 비어있는 EQ 포인트 발견 (인덱스 %d).":                      "Warning: Line %d, empty EQ point (index %d).",
+		"경고: Line %d, 비정상적인 주파수 값 무시: %s":                            "Warning: Line %d, ignoring invalid frequency: %s",
+		"경고: Line %d, 숫자 변환 오류 무시 ('%s'): %v, %v":                    "Warning: Line %d, ignoring number parse error ('%s'): %v, %v",
+		"경고: Line %d, 숫자 변환 오류 무시: '%s'":                             "Warning: Line %d, ignoring number parse error: '%s'",
+		"경고: Line %d, 열이 부족한 라인 무시: '%s'":                            "Warning: Line %d, ignoring line with too few columns: '%s'",
+		"경고: Line %d, 잘못된 Preamp 값 무시: '%s'":                         "Warning: Line %d, ignoring invalid Preamp value: '%s'",
+		"경고: Line %d, 잘못된 게인 값 (NaN or Inf) 무시 at freq %s":           "Warning: Line %d, ignoring invalid gain (NaN or Inf) at freq %s",
+		"경고: Line %d, 잘못된 채널 이름 무시: '%s'":                            "Warning: Line %d, ignoring invalid channel name: '%s'",
+		"경고: Line %d, 잘못된 포인트 형식 무시 (항목 %d개): '%s'":                  "Warning: Line %d, ignoring malformed point (%d fields): '%s'",
+		"경고: Line %d, 잘못된 필터 값 무시: '%s'":                             "Warning: Line %d, ignoring invalid filter values: '%s'",
+		"경고: Line %d, 지원하지 않는 Equalizer APO 명령 무시: '%s'":             "Warning: Line %d, ignoring unsupported Equalizer APO command: '%s'",
+		"경고: Line %d, 지원하지 않는 필터 종류 무시: '%s'":                        "Warning: Line %d, ignoring unsupported filter type: '%s'",
+		"경고: NormalizePreamp 입력에서 잘못된 게인 값 발견 (freq: %s). 0.0으로 처리.": "Warning: invalid gain in NormalizePreamp input (freq: %s). Treating it as 0.0.",
+		"경고: PEQ 근사 실패 (%v).":                                        "Warning: PEQ fit failed (%v).",
+		"경고: raw 측정값을 사용할 수 없습니다 (%v).":                              "Warning: cannot use the raw measurement (%v).",
+		"경고: 결과 포맷팅 중 잘못된 게인 값 발견 (freq: %s). 0.0으로 대체.":             "Warning: invalid gain while formatting the result (freq: %s). Replaced with 0.0.",
+		"경고: 곡선 합산 중 보간 실패 (%v). 해당 곡선을 건너뜁니다.":                      "Warning: interpolation failed while summing curves (%v). Skipping that curve.",
+		"경고: 데이터 포인트 개수(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너뜁니다.":            "Warning: fewer data points (%d) than the window size (%d). Skipping smoothing.",
+		"경고: 레이어 보간기 생성 실패 (%v). 레이어를 건너뜁니다.":                        "Warning: failed to build the layer interpolator (%v). Skipping the layer.",
+		"경고: 보간 응답 최대값 계산 실패 (%v). 포인트 최대값을 사용합니다.":                  "Warning: failed to compute the interpolated peak (%v). Using the point maximum.",
+		"경고: 스무딩 계산 중 잘못된 값 발견 (index: %d). 건너뜁니다.":                  "Warning: invalid value during smoothing (index: %d). Skipping.",
+		"경고: 스무딩 윈도우 내 유효 값 없음 (index: %d). 원래 값 유지.":                "Warning: no valid values in the smoothing window (index: %d). Keeping the original value.",
+		"경고: 스무딩 평균 계산 결과가 잘못됨 (index: %d). 원래 값 유지.":                "Warning: invalid smoothing average (index: %d). Keeping the original value.",
+		"경고: 이동 평균 윈도우 크기(%d)는 1보다 큰 홀수여야 합니다. 스무딩을 건너뜁니다.":          "Warning: the moving-average window size (%d) must be an odd number greater than 1. Skipping smoothing.",
+		"경고: 채널마다 EQ 가 다릅니다. 첫 번째 채널 (%s) 만 사용합니다.":                  "Warning: channels have different EQs. Using only the first channel (%s).",
+		"경고: 체감 음량 계산 실패 (%v).":                                      "Warning: perceived loudness calculation failed (%v).",
+		"경고: 타겟 보간 실패 (%v). 합집합 방식으로 적용합니다.":                         "Warning: target interpolation failed (%v). Applying it on the union grid.",
+		"경고: 헤더 체크섬이 GraphicEQ 데이터와 일치하지 않습니다 (파일이 수정되었을 수 있음).":     "Warning: the header checksum does not match the GraphicEQ data (the file may have been edited).",
+		"포인트 수 %d -> %d 로 줄이는 중...":                                  "Reducing points %d -> %d...",
+	},
+}
+
+// 번역할 수 있는 오류 생성 (fmt.Errorf 와 같은 형식, 웹 응답에서 요청 언어로 출력됨)
+func errorf(format string, args ...interface{}) error {
+	return &eq.Message{Format: format, Args: args}
+}
+
+// 원문 번역 (카탈로그에 없으면 원문)
+func (l language) translate(text string) string {
+	if translated, ok := catalogs[l][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// T 는 형식 문자열을 번역한 뒤 args 를 채웁니다 (오류 값도 번역). args 가 없으면 번역된 문구를 그대로 반환합니다.
+func (l language) T(format string, args ...interface{}) string {
+	if len(args) == 0 {
+		return l.translate(format)
+	}
+	localized := make([]interface{}, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = eq.Localize(err, l.translate)
+		}
+		localized[i] = arg
+	}
+	return fmt.Sprintf(l.translate(format), localized...)
+}
+
+// Err 는 오류 메시지를 번역합니다.
+func (l language) Err(err error) string {
+	return eq.Localize(err, l.translate)
+}
+
+// 요청 언어 결정 (?lang= > 쿠키 > Accept-Language > 기본값). ?lang= 으로 고른 언어는 쿠키에 저장합니다.
+func negotiateLang(w http.ResponseWriter, r *http.Request) language {
+	if l, ok := parseLang(r.URL.Query().Get("lang")); ok {
+		http.SetCookie(w, &http.Cookie{
+			Name:     langCookieName,
+			Value:    string(l),
+			Path:     "/",
+			MaxAge:   365 * 24 * 60 * 60,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return l
+	}
+	if cookie, err := r.Cookie(langCookieName); err == nil {
+		if l, ok := parseLang(cookie.Value); ok {
+			return l
+		}
+	}
+	return acceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+// 언어 태그 (예: en-US) 를 지원 언어로 변환
+func parseLang(tag string) (language, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	for _, l := range supportedLangs {
+		if base == string(l) {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// Accept-Language 헤더에서 q 값이 가장 높은 지원 언어 선택 (없으면 기본 언어)
+func acceptLanguage(header string) language {
+	type candidate struct {
+		lang language
+		q    float64
+	}
+	var candidates []candidate
+	for _, field := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(field, ";")
+		l, ok := parseLang(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{l, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return supportedLangs[0]
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// 로그/콘솔 메시지 언어 (환경 변수 AHTVC_LANG, 기본 한국어)
+var logLang = supportedLangs[0]
+
+// 환경 변수로 로그 언어를 정하고 라이브러리 경고도 같은 언어로 출력
+func initLogLang() {
+	if l, ok := parseLang(os.Getenv(envLang)); ok {
+		logLang = l
+	}
+	eq.Logf = func(format string, args ...interface{}) {
+		fmt.Println(logLang.T(strings.TrimSuffix(format, "\n"), args...))
+	}
+}
+
+// 번역한 로그 출력 (끝의 줄바꿈은 log 가 붙임)
+func logPrintf(format string, args ...interface{}) {
+	log.Print(logLang.T(strings.TrimSuffix(format, "\n"), args...))
+}
+
+// 번역한 로그 출력 후 종료
+func logFatalf(format string, args ...interface{}) {
+	log.Fatal(logLang.T(strings.TrimSuffix(format, "\n"), args...))
+}
+
+// 번역한 표준 오류 출력 (한 줄, CLI 오류와 사용법)
+func stderrPrintf(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, logLang.T(strings.TrimSuffix(format, "\n"), args...))
+}
+
+// 번역한 콘솔 출력 (한 줄)
+func consolePrintf(format string, args ...interface{}) {
+	fmt.Println(logLang.T(strings.TrimSuffix(format, "\n"), args...))
+}
+
+// 번역 함수가 없는 템플릿 파싱용 함수 (언어별 템플릿에서 교체됨)
+var templateFuncs = template.FuncMap{"t": langKorean.T}
+
+// 언어별 템플릿 (같은 정의에 "t" 함수만 해당 언어로 바꿈)
+func localizeTemplate(base *template.Template) map[language]*template.Template {
+	templates := map[language]*template.Template{}
+	for _, l := range supportedLangs {
+		templates[l] = template.Must(base.Clone()).Funcs(template.FuncMap{"t": l.T})
+	}
+	return templates
+}
+
+// 응답 데이터의 언어에 맞는 템플릿 (언어가 없으면 기본 언어)
+func templateFor(templates map[language]*template.Template, resultData map[string]interface{}) *template.Template {
+	if l, ok := resultData["Lang"].(language); ok {
+		if t, found := templates[l]; found {
+			return t
+		}
+	}
+	return templates[supportedLangs[0]]
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"ahtvc/eq"
+)
+
+func TestTemplateLocales(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+		header  string
+		want    []string
+		notWant string
+	}{
+		{"index 기본", handleConvert, "/", "", []string{`<html lang="ko">`, "변환하기", "출력 옵션"}, `value="Convert"`},
+		{"index Accept-Language", handleConvert, "/", "en-US,en;q=0.9,ko;q=0.8", []string{`<html lang="en">`, `value="Convert"`, "Output options"}, "변환하기"},
+		{"index ?lang=", handleConvert, "/?lang=en", "ko", []string{`<html lang="en">`, "Output options"}, "출력 옵션"},
+		{"compare 기본", handleCompare, "/compare", "", []string{`<html lang="ko">`, "비교하기"}, "Compare EQ files"},
+		{"compare en", handleCompare, "/compare?lang=en", "", []string{`<html lang="en">`, `value="Compare"`, "Back to the converter"}, "비교하기"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		if tt.header != "" {
+			req.Header.Set("Accept-Language", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		tt.handler(rec, req)
+		body := rec.Body.String()
+		for _, want := range tt.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: %q 없음", tt.name, want)
+			}
+		}
+		if strings.Contains(body, tt.notWant) {
+			t.Errorf("%s: %q 가 있으면 안 됨", tt.name, tt.notWant)
+		}
+	}
+}
+
+func TestLangCookie(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/?lang=en", nil))
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == langCookieName {
+			cookie = c
+		}
+	}
+	if cookie == nil || cookie.Value != "en" {
+		t.Fatalf("언어 쿠키 = %v, want en", cookie)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "ko")
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	handleConvert(rec, req)
+	if !strings.Contains(rec.Body.String(), `<html lang="en">`) {
+		t.Error("쿠키로 고른 언어가 Accept-Language 보다 우선해야 함")
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   language
+	}{
+		{"", langKorean},
+		{"en", langEnglish},
+		{"en-GB,en;q=0.8", langEnglish},
+		{"ko-KR,ko;q=0.9,en-US;q=0.8", langKorean},
+		{"fr-FR,en;q=0.5,ko;q=0.7", langKorean},
+		{"fr-FR, de;q=0.9", langKorean},
+		{"ko;q=0, en;q=0.1", langEnglish},
+		{"en;q=abc", langKorean},
+	}
+	for _, tt := range tests {
+		if got := acceptLanguage(tt.header); got != tt.want {
+			t.Errorf("acceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	req := newUploadRequest(t, "/?format=json", map[string][]byte{"sourceHarmanFile": []byte("no eq here\n")}, map[string]string{csrfFieldName: testCSRFToken}, testCSRFToken)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "Input file parse error: Unrecognized file format (GraphicEQ, AutoEQ CSV, squig.link and REW exports are supported)") {
+		t.Errorf("파서 오류가 번역되지 않음: %s", rec.Body.String())
+	}
+
+	if got := langKorean.Err(errCSRFMismatch); got != errCSRFMismatch.Error() {
+		t.Errorf("한국어 오류 = %q, want 원문", got)
+	}
+}
+
+// 템플릿과 코드의 번역 대상 원문이 모두 영어 카탈로그에 있는지 확인
+func TestCatalogCoverage(t *testing.T) {
+	literal := regexp.MustCompile(`(?:\{\{t |(?:errorf|notFoundf|logf|logPrintf|logFatalf|consolePrintf|stderrPrintf)\(|[lL]ang\.T\()("(?:[^"\\]|\\.)*")`)
+	// 번역을 거치지 않는 출력/오류 (원문이 한국어면 카탈로그 함수를 써야 함)
+	raw := regexp.MustCompile(`(?:fmt\.(?:Errorf|Fprint[a-z]*|Print[a-z]*)|http\.Error|errors\.New)\([^"\n(]*("(?:[^"\\]|\\.)*")`)
+	hangul := regexp.MustCompile(`\p{Hangul}`)
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eqFiles, err := filepath.Glob(filepath.Join("eq", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checked := 0
+	for _, file := range append(files, eqFiles...) {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range literal.FindAllStringSubmatch(string(content), -1) {
+			text, err := strconv.Unquote(match[1])
+			if err != nil {
+				t.Fatalf("%s: %s: %v", file, match[1], err)
+			}
+			text = strings.TrimSuffix(text, "\n") // 로그 메시지는 줄바꿈 없이 번역
+			if _, ok := catalogs[langEnglish][text]; !ok {
+				t.Errorf("%s: 영어 번역 없음: %q", file, text)
+			}
+			checked++
+		}
+		for _, match := range raw.FindAllStringSubmatch(string(content), -1) {
+			if hangul.MatchString(match[1]) {
+				t.Errorf("%s: 번역하지 않고 출력하는 원문: %s", file, match[1])
+			}
+		}
+	}
+	if checked < 100 {
+		t.Errorf("번역 대상 원문이 %d개뿐임 (정규식 확인 필요)", checked)
+	}
+}
+
+// AHTVC_LANG 로 고른 언어로 서버 로그와 라이브러리 경고를 출력
+func TestLogLang(t *testing.T) {
+	savedLogf := eq.Logf
+	t.Cleanup(func() {
+		logLang = supportedLangs[0]
+		eq.Logf = savedLogf
+		log.SetOutput(os.Stderr)
+	})
+	t.Setenv(envLang, "en")
+	initLogLang()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	logPrintf("변환 실패 (%s): %v\n", "a.txt", errorf("보간할 포인트가 없습니다"))
+	if got := buf.String(); !strings.HasSuffix(got, "Conversion failed (a.txt): No points to interpolate\n") {
+		t.Errorf("로그 = %q", got)
+	}
+
+	// 라이브러리 오류도 번역되어 경고에 섞이지 않음
+	_, err := eq.ExportProfiles[0].Export(nil, "x")
+	if got := logLang.Err(err); got != "No EQ data to export" {
+		t.Errorf("내보내기 오류 = %q", got)
+	}
+}
+
+// JSON 응답의 품질 지표 대역 이름도 요청 언어로 번역
+func TestLocalizedMetrics(t *testing.T) {
+	silenceLogs(t)
+	req := newUploadRequest(t, "/?format=json&lang=en", map[string][]byte{"sourceHarmanFile": readCoreEQ(t)}, map[string]string{csrfFieldName: testCSRFToken}, testCSRFToken)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	var response struct{ Results []convertResult }
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil || len(response.Results) == 0 {
+		t.Fatalf("JSON 응답 오류: %v\n%s", err, rec.Body.String())
+	}
+	band := response.Results[0].Metrics.Bands[0]
+	if band.Key != "bass" || band.Name != "Bass (20-250Hz)" {
+		t.Errorf("대역 = %+v, want bass / Bass (20-250Hz)", band)
+	}
+}
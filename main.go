@@ -2,80 +2,34 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
-	"fmt"
+	"flag"
 	"html/template"
-	"io"
-	"log"
-	"math"
 	"net"
 	"net/http"
+	"os"
 	"os/exec"
+	"os/signal"
+	"path"
 	"runtime"
-	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
-	// 외부 라이브러리 임포트 없음
-)
-
-// AutoEQ 데이터를 저장할 구조체
-type AutoEQData map[int]float64
-
-// 추가할 EQ 포인트를 위한 구조체 (정렬을 위해 필요)
-type eqPoint struct {
-	Freq int
-	Gain float64
-}
 
-// --- 상수 정의 ---
-
-// Harman -> VDSF 변환 EQ 데이터 (실제 값 포함)
-var harmanToVdsfEQ = parseConstantEQ(`
-GraphicEQ: 20 -0.7; 21 -0.8; 22 -0.9; 23 -1.0; 24 -1.1; 26 -1.2; 27 -1.3; 29 -1.3; 30 -1.4; 32 -1.4; 34 -1.4; 36 -1.3; 38 -1.2; 40 -1.1; 43 -1.0; 45 -0.9; 48 -0.8; 50 -0.7; 53 -0.6; 56 -0.3; 59 -0.2; 63 -0.0; 66 0.2; 70 0.3; 74 0.5; 78 0.8; 83 1.0; 87 1.2; 92 1.4; 97 1.7; 103 1.9; 109 2.3; 115 2.6; 121 2.7; 128 3.0; 136 3.3; 143 3.5; 151 3.7; 160 3.8; 169 4.0; 178 4.0; 188 4.1; 199 4.1; 210 4.2; 222 4.3; 235 4.4; 248 4.5; 262 4.6; 277 4.7; 292 4.7; 309 4.8; 326 4.7; 345 4.7; 364 4.7; 385 4.7; 406 4.7; 429 4.7; 453 4.7; 479 4.8; 506 4.9; 534 4.9; 565 5.0; 596 5.0; 630 5.0; 665 5.1; 703 5.0; 743 5.0; 784 4.9; 829 4.8; 875 4.7; 924 4.6; 977 4.6; 1032 4.5; 1090 4.3; 1151 4.3; 1216 4.2; 1284 4.1; 1357 4.1; 1433 4.0; 1514 3.9; 1599 3.9; 1689 3.9; 1784 3.8; 1885 3.7; 1991 3.7; 2103 3.6; 2221 3.5; 2347 3.5; 2479 3.4; 2618 3.4; 2766 3.2; 2921 3.2; 3086 3.0; 3260 2.9; 3443 2.7; 3637 2.4; 3842 2.2; 4058 1.9; 4287 1.7; 4528 1.4; 4783 1.0; 5052 0.8; 5337 0.5; 5637 0.2; 5955 0.0; 6290 -0.1; 6644 -0.2; 7018 0.0; 7414 0.1; 7831 0.5; 8272 1.2; 8738 2.7; 9230 4.2; 9749 5.4; 10298 5.3; 10878 4.4; 11490 4.0; 12137 4.2; 12821 4.7; 13543 5.3; 14305 5.5; 15110 5.1; 15961 4.6; 16860 4.2; 17809 4.0; 18812 3.9; 19871 3.9
-`)
-
-// 추가할 EQ 설정 (Wavelet EQ)
-var x2EQPoints = func() []eqPoint {
-	points := []eqPoint{
-		{Freq: 62, Gain: 1.6}, {Freq: 125, Gain: 0.4}, {Freq: 250, Gain: -0.6},
-		{Freq: 500, Gain: 0.0}, {Freq: 1000, Gain: -0.4}, {Freq: 2000, Gain: -0.7},
-		{Freq: 4000, Gain: -0.5}, {Freq: 8000, Gain: -0.1}, {Freq: 16000, Gain: 0.3},
-	}
-	sort.Slice(points, func(i, j int) bool { return points[i].Freq < points[j].Freq })
-	return points
-}()
-
-// 이동 평균 스무딩 파라미터
-const (
-	smoothStartFreq     = 8000.0 // 스무딩 시작 주파수
-	movingAverageWindow = 5      // 이동 평균 창 크기 (홀수 권장, 클수록 부드러움)
+	"ahtvc/eq" // 외부 라이브러리 임포트 없음
 )
 
 // HTML 템플릿
-var indexTemplate = template.Must(template.New("").Parse(`
+var indexTemplate = template.Must(template.New("").Funcs(templateFuncs).Parse(`
 <!DOCTYPE html>
-<html>
+<html lang="{{.Lang}}">
 <head>
     <meta charset="UTF-8">
     <title>AutoEQ Harman to VDSF Converter (AHTVC)</title>
-     <style>
-        body { font-family: sans-serif; padding: 20px; max-width: 800px; margin: auto; background-color: #f0f0f0; color: #333; }
-        h1 { color: #1a1a1a; border-bottom: 2px solid #ccc; padding-bottom: 10px; }
-		p { line-height: 1.6; }
-        label { display: block; margin-top: 15px; font-weight: bold; color: #555; }
-        input[type=file] { margin-top: 5px; padding: 8px; border: 1px solid #ccc; border-radius: 4px; background-color: #fff; }
-		input[type=submit] { padding: 10px 20px; background-color: #007bff; color: white; border: none; border-radius: 4px; cursor: pointer; font-size: 1em; margin-top: 15px; transition: background-color 0.2s; }
-		input[type=submit]:hover { background-color: #0056b3; }
-        textarea { width: 95%; height: 150px; margin-top: 10px; font-family: monospace; white-space: pre; overflow-wrap: normal; overflow-x: scroll; display: block; border: 1px solid #ccc; border-radius: 4px; padding: 10px; background-color: #fff; }
-        .error { color: #D8000C; margin-top: 15px; border: 1px solid #D8000C; padding: 15px; background-color: #FFD2D2; border-radius: 4px; }
-        .result-container { margin-top: 25px; }
-        .result-box { margin-bottom: 25px; padding: 20px; border: 1px solid #ccc; background-color: #fff; border-radius: 4px; box-shadow: 0 2px 4px rgba(0,0,0,0.1); }
-		.filename { font-weight: bold; font-family: monospace; margin-bottom: 10px; font-size: 1.1em; color: #333; }
-		.action-buttons button { margin-right: 10px; cursor: pointer; font-size: 0.9em; padding: 5px 10px; margin-top: 10px; border: 1px solid #ccc; background-color: #eee; border-radius: 4px; transition: background-color 0.2s; }
-        .action-buttons button:hover { background-color: #ddd; }
-		.copy-feedback { font-size: 0.8em; color: green; margin-left: 5px; display: none; font-weight: bold; }
-    </style>
+    {{template "style"}}
 	<script>
 		function copyToClipboard(elementId, feedbackId) {
 			var copyText = document.getElementById(elementId);
@@ -87,17 +41,17 @@ var indexTemplate = template.Must(template.New("").Parse(`
 					var feedback = document.getElementById(feedbackId);
 					if (feedback) { feedback.style.display = 'inline'; setTimeout(function() { feedback.style.display = 'none'; }, 1500); }
 				}, function(err) {
-					console.error('클립보드 복사 실패:', err);
+					console.error('Clipboard copy failed:', err);
 					try {
 						var successful = document.execCommand('copy');
 						if (successful) { var feedback = document.getElementById(feedbackId); if (feedback) { feedback.style.display = 'inline'; setTimeout(function() { feedback.style.display = 'none'; }, 1500); }
-						} else { alert('클립보드 복사에 실패했습니다.'); }
-					} catch (errFallback) { alert('클립보드 복사에 실패했습니다.'); console.error('Fallback copy command failed:', errFallback); }
+						} else { alert({{t "클립보드 복사에 실패했습니다."}}); }
+					} catch (errFallback) { alert({{t "클립보드 복사에 실패했습니다."}}); console.error('Fallback copy command failed:', errFallback); }
 				});
-			} catch (errGlobal) { alert('클립보드 API를 사용할 수 없습니다.'); console.error('Clipboard API error:', errGlobal); }
+			} catch (errGlobal) { alert({{t "클립보드 API를 사용할 수 없습니다."}}); console.error('Clipboard API error:', errGlobal); }
 		}
 		function downloadTextFile(filename, text) {
-			if (typeof filename !== 'string' || typeof text !== 'string') { console.error("Download Error: Invalid types", filename, text); alert("파일 다운로드 오류: 타입 오류"); return; }
+			if (typeof filename !== 'string' || typeof text !== 'string') { console.error("Download Error: Invalid types", filename, text); alert({{t "파일 다운로드 오류: 타입 오류"}}); return; }
 			var element = document.createElement('a');
 			var blob = new Blob([text], {type: 'text/plain;charset=utf-8'});
 			var url = URL.createObjectURL(blob);
@@ -113,341 +67,726 @@ var indexTemplate = template.Must(template.New("").Parse(`
             const button = event.target;
             const filename = button.dataset.filename;
             const content = button.dataset.content;
-            if (filename && content) { downloadTextFile(filename, content); } else { console.error("Download Error: Missing data attrs", button); alert("파일 다운로드 오류: 데이터 없음"); }
+            if (filename && content) { downloadTextFile(filename, content); } else { console.error("Download Error: Missing data attrs", button); alert({{t "파일 다운로드 오류: 데이터 없음"}}); }
         }
 	</script>
 </head>
 <body>
+    {{template "langs" .}}
     <h1>AutoEQ Harman to VDSF Converter (AHTVC)</h1>
-    <p>이어폰/헤드폰의 <b>Harman 타겟 AutoEQ 파일</b>을 업로드하세요.</p>
-	<p>자동으로 VDSF 타겟 기반의 EQ 파일을 생성합니다 (For Wavelet).</p>
-    <form method="POST" enctype="multipart/form-data">
-        <label for="sourceHarmanFile">Harman 타겟 EQ 파일 (.txt):</label>
-        <input type="file" id="sourceHarmanFile" name="sourceHarmanFile" accept=".txt" required>
+    <p>{{t "이어폰/헤드폰의 Harman 타겟 AutoEQ 파일을 업로드하세요."}}</p>
+	<p>{{t "자동으로 VDSF 타겟 기반의 EQ 파일을 생성합니다 (For Wavelet)."}}</p>
+	<p><a href="/compare">{{t "EQ 파일 비교"}}</a></p>
+    {{if .AutoEQEnabled}}
+    <form method="GET">
+        <label for="q">{{t "AutoEQ 저장소에서 기기 검색:"}}</label>
+        <input type="text" id="q" name="q" value="{{.DeviceQuery}}" placeholder="{{t "예: HD 650"}}">
+        <input type="submit" value="{{t "검색"}}">
+    </form>
+    {{with .Devices}}<ul>{{range .}}<li><a href="/?device={{.Path}}">{{.Name}}</a> [{{.Source}}]</li>{{end}}</ul>
+    {{else}}{{with .DeviceQuery}}<p>{{t "'%s' 와 일치하는 기기가 없습니다." .}}</p>{{end}}{{end}}
+    {{end}}
+    <form id="convertForm" method="POST" enctype="multipart/form-data">
+        <input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+        <label for="sourceHarmanFile">{{t "Harman 타겟 EQ 파일 (GraphicEQ/Equalizer APO .txt, AutoEQ .csv 또는 설정 폴더 .zip):"}}</label>
+        <input type="file" id="sourceHarmanFile" name="sourceHarmanFile" accept=".txt,.csv,.zip"{{if not (or .Rerun .Device)}} required{{end}}>
+        {{with .Device}}<input type="hidden" name="device" value="{{.Path}}">
+        <p>{{t "AutoEQ 저장소 기기:"}} <b>{{.Name}}</b> [{{.Source}}] ({{t "파일을 선택하지 않으면 저장소 파일을 사용합니다"}})</p>{{end}}
+        {{with .Rerun}}<input type="hidden" name="historyID" value="{{.ID}}">
+        <p>{{t "다시 실행:"}} <b>{{.SourceFilename}}</b>{{with .RawFilename}} ({{t "측정값"}} {{.}}){{end}} ({{t "파일을 선택하지 않으면 저장된 입력 파일을 사용합니다"}})</p>{{end}}
+        {{if gt (len .Pipelines) 1}}
+        <label for="pipeline">{{t "변환 파이프라인:"}}</label>
+        <select id="pipeline" name="pipeline">
+            {{range .Pipelines}}<option value="{{.Name}}"{{if eq .Name $.SelectedPipeline}} selected{{end}}>{{.Name}}{{with .Description}} - {{t .}}{{end}}</option>
+            {{end}}
+        </select>
+        {{end}}
+        <label for="rawMeasurementFile">{{t "Raw 측정값 파일 (선택, AutoEQ CSV / squig.link / REW 내보내기):"}}</label>
+        <input type="file" id="rawMeasurementFile" name="rawMeasurementFile" accept=".txt,.csv">
+        <details>
+            <summary>{{t "출력 옵션"}}</summary>
+            <label for="precision">{{t "소수점 자리수 (0~3):"}}</label>
+            <input type="number" id="precision" name="precision" min="0" max="3" placeholder="1" value="{{.Form.precision}}">
+            <label for="quantizeStep">{{t "게인 양자화 단위 (dB, 예: 0.5):"}}</label>
+            <input type="number" id="quantizeStep" name="quantizeStep" min="0" max="6" step="0.01" placeholder="{{t "사용 안 함"}}" value="{{.Form.quantizeStep}}">
+            <label for="minFreq">{{t "최소 주파수 (Hz):"}}</label>
+            <input type="number" id="minFreq" name="minFreq" min="0" max="30000" placeholder="{{t "제한 없음"}}" value="{{.Form.minFreq}}">
+            <label for="maxFreq">{{t "최대 주파수 (Hz):"}}</label>
+            <input type="number" id="maxFreq" name="maxFreq" min="0" max="30000" placeholder="{{t "제한 없음"}}" value="{{.Form.maxFreq}}">
+            <label for="maxPoints">{{t "최대 포인트 수 (예: 127):"}}</label>
+            <input type="number" id="maxPoints" name="maxPoints" min="0" max="10000" placeholder="{{t "제한 없음"}}" value="{{.Form.maxPoints}}">
+            <label for="preampMode">{{t "Preamp 계산 방식:"}}</label>
+            <select id="preampMode" name="preampMode">
+                <option value="">{{t "파이프라인 설정"}}</option>
+                <option value="sampled"{{if eq .Form.preampMode "sampled"}} selected{{end}}>{{t "포인트 최대값 (sampled)"}}</option>
+                <option value="interpolated"{{if eq .Form.preampMode "interpolated"}} selected{{end}}>{{t "보간 응답 최대값 (interpolated)"}}</option>
+            </select>
+            <label for="preampHeadroom">{{t "추가 여유 (dB, 예: 1):"}}</label>
+            <input type="number" id="preampHeadroom" name="preampHeadroom" min="0" max="30" step="0.1" placeholder="0" value="{{.Form.preampHeadroom}}">
+            <label><input type="checkbox" name="separatePreamp" value="1"{{if .Form.separatePreamp}} checked{{end}}> {{t "게인을 내리는 대신 별도 Preamp 라인으로 출력"}}</label>
+            <label><input type="checkbox" name="levelMatch" value="1"{{if .Form.levelMatch}} checked{{end}}> {{t "결과 간 체감 음량 맞춤 (ITU-R 468, A/B 비교용)"}}</label>
+            <label for="loudnessLevels">{{t "등청감 보정 청취 음량 (phon, 쉼표로 구분, 예: 60,40):"}}</label>
+            <input type="text" id="loudnessLevels" name="loudnessLevels" placeholder="{{t "사용 안 함"}}" value="{{.Form.loudnessLevels}}">
+            <label for="loudnessRef">{{t "기준 음량 (phon, 20~90):"}}</label>
+            <input type="number" id="loudnessRef" name="loudnessRef" min="20" max="90" step="1" placeholder="80" value="{{.Form.loudnessRef}}">
+        </details>
+        {{if .HistoryEnabled}}
+        <label for="presetName">{{t "현재 설정을 프리셋으로 저장 (선택, 이름):"}}</label>
+        <input type="text" id="presetName" name="presetName" placeholder="{{t "저장 안 함"}}">
+        {{end}}
         <br><br>
-        <input type="submit" value="변환하기">
+        <input type="submit" value="{{t "변환하기"}}">
     </form>
+    {{with .SavedPreset}}<p>{{t "프리셋 저장됨:"}} <b>{{.}}</b></p>{{end}}
+    {{with .InputFormat}}<p>{{t "감지된 입력 형식:"}} <b>{{.}}</b></p>{{end}}
+    {{with .PreviewToken}}{{template "preview" .}}{{end}}
     <div class="result-container">
-		{{if .Filename1}}
+		{{range $i, $result := .Results}}
         <div class="result-box">
-            <div class="filename">{{.Filename1}}</div>
-            <textarea id="result1Text" readonly>{{.Result1}}</textarea>
+            <div class="filename" id="resultName{{$i}}">{{.Filename}}</div>
+            {{if .LevelMatched}}<p>{{t "음량 맞춤 오프셋: %+.2f dB" .LevelOffset}}</p>{{end}}
+            <textarea id="resultText{{$i}}" readonly>{{.Content}}</textarea>
 			<div class="action-buttons">
-				<button type="button" onclick="copyToClipboard('result1Text', 'copyFeedback1')">클립보드 복사</button>
-				<span class="copy-feedback" id="copyFeedback1">복사됨!</span>
-				<button type="button" data-filename="{{.Filename1}}" data-content="{{.Result1}}" onclick="handleDownloadClick(event)">파일로 저장 (.txt)</button>
+				<button type="button" onclick="copyToClipboard('resultText{{$i}}', 'copyFeedback{{$i}}')">{{t "클립보드 복사"}}</button>
+				<span class="copy-feedback" id="copyFeedback{{$i}}">{{t "복사됨!"}}</span>
+				<button type="button" id="resultDownload{{$i}}" data-filename="{{.Filename}}" data-content="{{.Content}}" onclick="handleDownloadClick(event)">{{t "파일로 저장 (.txt)"}}</button>
 			</div>
-        </div>
-        {{end}}
-        {{if .Filename2}}
-        <div class="result-box">
-             <div class="filename">{{.Filename2}}</div>
-           <textarea id="result2Text" readonly>{{.Result2}}</textarea>
-		   <div class="action-buttons">
-				<button type="button" onclick="copyToClipboard('result2Text', 'copyFeedback2')">클립보드 복사</button>
-				<span class="copy-feedback" id="copyFeedback2">복사됨!</span>
-				<button type="button" data-filename="{{.Filename2}}" data-content="{{.Result2}}" onclick="handleDownloadClick(event)">파일로 저장 (.txt)</button>
-		   </div>
+			{{with .Exports}}{{template "exports" .}}{{end}}
+			{{with .Metrics}}{{template "metrics" .}}{{end}}
         </div>
         {{end}}
     </div>
-    {{if .Error}} <div class="error"> <strong>오류:</strong> <pre>{{.Error}}</pre> </div> {{end}}
+    {{if .Error}} <div class="error"> <strong>{{t "오류:"}}</strong> <pre>{{.Error}}</pre> </div> {{end}}
+    {{with .Presets}}
+    <details>
+        <summary>{{t "저장된 프리셋"}}</summary>
+        <ul>{{range .}}<li><a href="/?preset={{.Name}}">{{.Name}}</a></li>{{end}}</ul>
+    </details>
+    {{end}}
+    {{with .History}}
+    <details>
+        <summary>{{t "변환 기록"}}</summary>
+        <table class="metrics">
+            <tr><th>{{t "시각"}}</th><th>{{t "입력 파일"}}</th><th>{{t "파이프라인"}}</th><th>{{t "출력"}}</th><th></th></tr>
+            {{range .}}<tr><td>{{.Time.Local.Format "2006-01-02 15:04"}}</td><td>{{.SourceFilename}}</td><td>{{.Pipeline}}</td><td>{{t "%d개" (len .Outputs)}}</td><td><a href="/?history={{.ID}}">{{t "다시 실행"}}</a></td></tr>
+            {{end}}
+        </table>
+    </details>
+    {{end}}
 </body>
 </html>
+{{define "style"}}
+    <style>
+        body { font-family: sans-serif; padding: 20px; max-width: 800px; margin: auto; background-color: #f0f0f0; color: #333; }
+        h1 { color: #1a1a1a; border-bottom: 2px solid #ccc; padding-bottom: 10px; }
+		p { line-height: 1.6; }
+        label { display: block; margin-top: 15px; font-weight: bold; color: #555; }
+        input[type=file] { margin-top: 5px; padding: 8px; border: 1px solid #ccc; border-radius: 4px; background-color: #fff; }
+		input[type=submit] { padding: 10px 20px; background-color: #007bff; color: white; border: none; border-radius: 4px; cursor: pointer; font-size: 1em; margin-top: 15px; transition: background-color 0.2s; }
+		input[type=submit]:hover { background-color: #0056b3; }
+        textarea { width: 95%; height: 150px; margin-top: 10px; font-family: monospace; white-space: pre; overflow-wrap: normal; overflow-x: scroll; display: block; border: 1px solid #ccc; border-radius: 4px; padding: 10px; background-color: #fff; }
+        .error { color: #D8000C; margin-top: 15px; border: 1px solid #D8000C; padding: 15px; background-color: #FFD2D2; border-radius: 4px; }
+        .result-container { margin-top: 25px; }
+        .result-box { margin-bottom: 25px; padding: 20px; border: 1px solid #ccc; background-color: #fff; border-radius: 4px; box-shadow: 0 2px 4px rgba(0,0,0,0.1); }
+		.filename { font-weight: bold; font-family: monospace; margin-bottom: 10px; font-size: 1.1em; color: #333; }
+		.action-buttons button { margin-right: 10px; cursor: pointer; font-size: 0.9em; padding: 5px 10px; margin-top: 10px; border: 1px solid #ccc; background-color: #eee; border-radius: 4px; transition: background-color 0.2s; }
+        .action-buttons button:hover { background-color: #ddd; }
+		.copy-feedback { font-size: 0.8em; color: green; margin-left: 5px; display: none; font-weight: bold; }
+		.metrics { margin-top: 15px; border-collapse: collapse; font-size: 0.9em; }
+		.metrics td, .metrics th { border: 1px solid #ddd; padding: 4px 10px; text-align: left; }
+		.metrics th { background-color: #f7f7f7; }
+		.preview { margin-top: 20px; padding: 15px; border: 1px dashed #007bff; background-color: #fff; border-radius: 4px; }
+		.preview svg { max-width: 100%; height: auto; }
+		.preview-status { font-size: 0.9em; color: #555; }
+		.langs { text-align: right; font-size: 0.9em; }
+		.langs a.active { font-weight: bold; color: #333; text-decoration: none; }
+    </style>
+{{end}}
+{{define "langs"}}
+    <p class="langs"><a href="?lang=ko"{{if eq .Lang "ko"}} class="active"{{end}}>한국어</a> | <a href="?lang=en"{{if eq .Lang "en"}} class="active"{{end}}>English</a></p>
+{{end}}
+{{define "preview"}}
+    <div class="preview" id="preview" data-token="{{.}}">
+        <strong>{{t "실시간 미리보기"}}</strong>
+        <p class="preview-status">{{t "출력 옵션이나 파이프라인을 바꾸면 파일을 다시 올리지 않고 결과와 그래프를 갱신합니다. 플레이어별 내보내기와 품질 지표는 변환하기를 눌러야 갱신됩니다."}}</p>
+        <p class="preview-status" id="previewStatus"></p>
+        <div id="previewPlot"></div>
+    </div>
+    <script>
+        (function() {
+            var preview = document.getElementById('preview');
+            var form = document.getElementById('convertForm');
+            var status = document.getElementById('previewStatus');
+            var token = preview.dataset.token;
+            var events = null, timer = null;
+            function render(update) {
+                document.getElementById('previewPlot').innerHTML = update.plot || '';
+                var results = update.results || [];
+                for (var i = 0; i < results.length; i++) {
+                    var text = document.getElementById('resultText' + i);
+                    if (!text) { status.textContent = {{t "출력 개수가 바뀌었습니다. 변환하기를 눌러 전체 결과를 다시 만드세요."}}; return; }
+                    text.value = results[i].content;
+                    document.getElementById('resultName' + i).textContent = results[i].filename;
+                    var button = document.getElementById('resultDownload' + i);
+                    button.dataset.filename = results[i].filename;
+                    button.dataset.content = results[i].content;
+                }
+                status.textContent = {{t "미리보기 갱신됨"}};
+            }
+            if (window.EventSource) {
+                events = new EventSource('/preview/events?previewToken=' + encodeURIComponent(token));
+                events.addEventListener('update', function(e) { render(JSON.parse(e.data)); });
+                events.addEventListener('expired', function() { events.close(); status.textContent = {{t "미리보기 세션이 만료되었습니다. 파일을 다시 변환해주세요"}}; });
+            }
+            function refresh() {
+                var data = new FormData(form);
+                data.delete('sourceHarmanFile');
+                data.delete('rawMeasurementFile');
+                data.delete('presetName');
+                data.set('previewToken', token);
+                status.textContent = {{t "미리보기 계산 중..."}};
+                fetch('/preview', {method: 'POST', body: data}).then(function(response) {
+                    return response.json().then(function(update) {
+                        if (update.error) { status.textContent = update.error; return; }
+                        if (!events || events.readyState !== EventSource.OPEN) { render(update); } // SSE 를 쓸 수 없으면 응답으로 갱신
+                    });
+                }).catch(function(err) { status.textContent = String(err); });
+            }
+            form.addEventListener('input', function(e) {
+                if (e.target.type === 'file' || e.target.name === 'presetName') return;
+                clearTimeout(timer);
+                timer = setTimeout(refresh, 300);
+            });
+            refresh();
+        })();
+    </script>
+{{end}}
+{{define "exports"}}
+			<div class="action-buttons">
+				<strong>{{t "플레이어별 내보내기:"}}</strong>
+				{{range .}}<button type="button" title="{{.Filename}}" data-filename="{{.Filename}}" data-content="{{.Content}}" onclick="handleDownloadClick(event)">{{.Profile}}</button>
+				{{end}}
+			</div>
+{{end}}
+{{define "metrics"}}
+			<table class="metrics">
+				<tr><th colspan="2">{{t "품질 지표"}}{{if .UsedRaw}} ({{t "Raw 측정값 기준"}}){{end}}</th></tr>
+				<tr><td>{{t "예측 선호도 점수 (Olive-Welti IEM)"}}</td><td>{{printf "%.1f" .Preference}}</td></tr>
+				<tr><td>{{t "VDSF 타겟 RMS 편차 (전체)"}}</td><td>{{printf "%.2f" .TotalRMS}} dB</td></tr>
+				{{range .Bands}}<tr><td>{{t "VDSF 타겟 RMS 편차"}} - {{.Name}}</td><td>{{printf "%.2f" .RMS}} dB</td></tr>
+				{{end}}<tr><td>{{t "최대 부스트"}}</td><td>{{printf "%.1f" .MaxBoost}} dB</td></tr>
+				<tr><td>{{t "전체 다이내믹 레인지"}}</td><td>{{printf "%.1f" .DynamicRange}} dB</td></tr>
+				<tr><td>{{t "필요 Preamp"}}</td><td>{{printf "%.1f" .Preamp}} dB</td></tr>
+			</table>
+{{end}}
 `))
 
+// 언어별 index 템플릿
+var indexTemplates = localizeTemplate(indexTemplate)
+
 // main 함수
 func main() {
-	listener, err := net.Listen("tcp", ":0")
+	initLogLang()
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		os.Exit(runCompareCLI(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatchCLI(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "autoeq" {
+		os.Exit(runAutoEQCLI(os.Args[2:]))
+	}
+
+	cfg, err := loadServerConfig(os.Args[1:])
 	if err != nil {
-		log.Fatalf("포트 찾기 실패: %v", err)
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		logFatalf("설정 오류: %v", err)
+	}
+
+	if cfg.PipelinesPath != "" {
+		activePipelines, err = eq.LoadConfigFile(cfg.PipelinesPath)
+		if err != nil {
+			logFatalf("파이프라인 정의 파일 오류 (%s): %v", cfg.PipelinesPath, err)
+		}
+		logPrintf("파이프라인 %d개 로드됨: %s\n", len(activePipelines.Pipelines), cfg.PipelinesPath)
+	}
+	if !cfg.NoHistory {
+		dataDir := cfg.DataDir
+		if dataDir == "" {
+			dataDir, err = defaultDataDir()
+		}
+		if err == nil {
+			activeHistory, err = openHistoryStore(dataDir)
+		}
+		if err != nil {
+			logPrintf("경고: 변환 기록 저장소를 열 수 없습니다 (%v). 기록 없이 실행합니다.\n", err)
+		} else {
+			logPrintf("변환 기록 저장 위치: %s\n", dataDir)
+		}
+	}
+	if cfg.AutoEQDir != "" {
+		activeAutoEQ, err = buildAutoEQIndex(cfg.AutoEQDir)
+		if err != nil {
+			logFatalf("AutoEQ 저장소 색인 오류 (%s): %v", cfg.AutoEQDir, err)
+		}
+		logPrintf("AutoEQ 기기 %d개 색인됨: %s\n", activeAutoEQ.Len(), cfg.AutoEQDir)
 	}
-	port := listener.Addr().(*net.TCPAddr).Port
-	address := fmt.Sprintf("http://localhost:%d", port)
-	listener.Close()
+
+	// 처음 확보한 리스너로 그대로 서비스 (포트를 닫았다가 다시 여는 경쟁 상태 방지)
+	listener, err := net.Listen("tcp", net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
+	if err != nil {
+		logFatalf("포트 열기 실패: %v", err)
+	}
+	address := browserURL(listener.Addr())
 
 	http.HandleFunc("/", handleConvert)
-	fmt.Printf("서버 주소: %s\n", address)
-	fmt.Println("웹 브라우저 여는 중...")
+	http.HandleFunc("/compare", handleCompare)
+	http.HandleFunc("/preview", handlePreview)
+	http.HandleFunc("/preview/events", handlePreviewEvents)
+	consolePrintf("서버 주소: %s\n", address)
+
+	if !cfg.NoBrowser {
+		consolePrintf("웹 브라우저 여는 중...")
+		go func() {
+			time.Sleep(1 * time.Second)
+			err := openBrowser(address)
+			if err != nil {
+				consolePrintf("브라우저 열기 오류: %v\n", err)
+			}
+		}()
+	}
 
+	server := newHTTPServer(nil)
+	server.RegisterOnShutdown(activePreviews.Close) // 미리보기 SSE 연결 종료
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownDone := make(chan struct{})
 	go func() {
-		time.Sleep(1 * time.Second)
-		err := openBrowser(address)
-		if err != nil {
-			fmt.Printf("브라우저 열기 오류: %v\n", err)
+		defer close(shutdownDone)
+		<-ctx.Done()
+		logPrintf("종료 신호 수신, 서버 종료 중...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logPrintf("서버 종료 오류: %v", err)
 		}
 	}()
 
-	log.Printf("포트 %d 에서 서버 시작...\n", port)
-	err = http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
+	logPrintf("%s 에서 서버 시작...\n", listener.Addr())
+	err = server.Serve(listener)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		log.Fatalf("서버 시작 실패: %v", err)
+		logFatalf("서버 시작 실패: %v", err)
+	}
+	<-shutdownDone
+	logPrintf("서버 종료.")
+}
+
+// 시작 시 불러온 파이프라인 정의 (-pipelines 미지정 시 내장 정의)
+var activePipelines = eq.DefaultConfig()
+
+// 출력 하나의 변환 결과
+type convertResult struct {
+	Name     string          `json:"name"`
+	Filename string          `json:"filename"`
+	Content  string          `json:"content"`
+	Exports  []eq.ExportFile `json:"exports"`
+	Metrics  eq.Metrics      `json:"metrics"`
+	// 음량 맞춤 적용 여부와 적용한 오프셋 (dB)
+	LevelMatched bool    `json:"levelMatched"`
+	LevelOffset  float64 `json:"levelOffset"`
+	// 최종 곡선 (미리보기 그래프용)
+	Curve eq.Curve `json:"-"`
+}
+
+// 파이프라인 선택 목록
+type pipelineChoice struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// 화면/JSON 응답용 파이프라인 선택 목록
+func pipelineChoices() []pipelineChoice {
+	choices := make([]pipelineChoice, len(activePipelines.Pipelines))
+	for i, pc := range activePipelines.Pipelines {
+		choices[i] = pipelineChoice{Name: pc.Name, Description: pc.Description}
 	}
-	log.Println("서버 종료.")
+	return choices
 }
 
 // 웹 요청 처리 핸들러
 func handleConvert(w http.ResponseWriter, r *http.Request) {
+	lang := negotiateLang(w, r)
 	resultData := map[string]interface{}{}
+	resultData["Lang"] = lang
+	resultData["CSRFToken"] = ensureCSRFToken(w, r)
+	resultData["Pipelines"] = pipelineChoices()
+	resultData["SelectedPipeline"] = activePipelines.Pipelines[0].Name
+	resultData["Form"] = map[string]string{}
+	resultData["AutoEQEnabled"] = activeAutoEQ != nil
+	addHistoryData(resultData)
+
+	if r.Method != http.MethodPost {
+		errPrefill := prefillForm(r, resultData)
+		if errPrefill == nil {
+			errPrefill = addDeviceData(r, resultData)
+		}
+		if errPrefill != nil {
+			status := http.StatusInternalServerError
+			if isNotFound(errPrefill) {
+				status = http.StatusNotFound
+			} else {
+				logPrintf("폼 채우기 오류: %v", errPrefill)
+			}
+			resultData["Error"] = lang.Err(errPrefill)
+			writeResponse(w, r, status, resultData)
+			return
+		}
+	}
 
 	if r.Method == http.MethodPost {
-		var sourceName string = "UnknownDevice"
+		if status, errUpload := prepareUpload(w, r); errUpload != nil {
+			resultData["Error"] = lang.T("업로드 거부: %v", errUpload)
+			writeResponse(w, r, status, resultData)
+			return
+		}
+		resultData["Form"] = formParams(r)
+
+		// 변환 기록에서 다시 실행하면 새 파일을 올리지 않아도 저장된 입력 파일 사용
+		var rerun *historyEntry
+		if historyID := strings.TrimSpace(r.FormValue("historyID")); historyID != "" {
+			entry, errEntry := activeHistory.Entry(historyID)
+			if errEntry != nil {
+				resultData["Error"] = lang.T("변환 기록 오류: %v", errEntry)
+				writeResponse(w, r, http.StatusBadRequest, resultData)
+				return
+			}
+			rerun = &entry
+			resultData["Rerun"] = entry.summary()
+		}
+		// AutoEQ 저장소에서 선택한 기기 (새 파일을 올리지 않으면 저장소 파일 사용)
+		var device *autoEQDevice
+		if relPath := r.FormValue("device"); relPath != "" {
+			selected, errDevice := activeAutoEQ.Device(relPath)
+			if errDevice != nil {
+				resultData["Error"] = lang.Err(errDevice)
+				writeResponse(w, r, http.StatusBadRequest, resultData)
+				return
+			}
+			device = &selected
+			resultData["Device"] = selected
+		}
 
+		var sourceFilename, sourceText string
+		sourcePath := "" // 타겟/기기 정보 추정용 원본 경로 (비어 있으면 파일 이름)
 		sourceHarmanFile, sourceHarmanHandler, errH := r.FormFile("sourceHarmanFile")
-		if errH != nil {
-			resultData["Error"] = "파일 업로드 오류: " + errH.Error()
-			w.WriteHeader(http.StatusBadRequest)
-			indexTemplate.Execute(w, resultData)
+		switch {
+		case errH == nil:
+			sourceHarmanBytes, configPath, errHRead := readConfigUpload(sourceHarmanFile)
+			sourceHarmanFile.Close()
+			if errHRead != nil {
+				resultData["Error"] = lang.T("파일 읽기 오류: %v", errHRead)
+				writeResponse(w, r, uploadErrorStatus(errHRead), resultData)
+				return
+			}
+			sourceFilename, sourceText = sourceHarmanHandler.Filename, string(sourceHarmanBytes)
+			if configPath != "" {
+				// ZIP 의 config.txt 는 기기 이름을 알 수 없으므로 ZIP 파일 이름 사용
+				sourcePath = configPath
+				if strings.EqualFold(path.Base(configPath), "config.txt") {
+					sourcePath = strings.TrimSuffix(sourceFilename, path.Ext(sourceFilename))
+				}
+			}
+		case errors.Is(errH, http.ErrMissingFile) && device != nil:
+			filename, content, errRead := activeAutoEQ.Read(*device)
+			if errRead != nil {
+				resultData["Error"] = lang.T("기기 파일 읽기 오류 (%s): %v", device.Path, errRead)
+				writeResponse(w, r, http.StatusInternalServerError, resultData)
+				return
+			}
+			sourceFilename, sourceText, sourcePath = filename, string(content), device.Path
+		case errors.Is(errH, http.ErrMissingFile) && rerun != nil:
+			sourceFilename, sourceText, sourcePath = rerun.SourceFilename, rerun.Source, rerun.SourcePath
+		default:
+			resultData["Error"] = lang.T("파일 업로드 오류: %v", errH)
+			writeResponse(w, r, http.StatusBadRequest, resultData)
 			return
 		}
-		defer sourceHarmanFile.Close()
-		sourceName = extractSourceName(sourceHarmanHandler.Filename)
 
-		sourceHarmanBytes, errHRead := io.ReadAll(sourceHarmanFile)
-		if errHRead != nil {
-			resultData["Error"] = "파일 읽기 오류: " + errHRead.Error()
-			w.WriteHeader(http.StatusInternalServerError)
-			indexTemplate.Execute(w, resultData)
+		sourceInput, sourceProvenance, errHParse := eq.ParseWithProvenance(sourceText)
+		if errHParse != nil {
+			resultData["Error"] = lang.T("입력 파일 파싱 오류: %v\n입력 파일 내용을 확인해주세요.", errHParse)
+			writeResponse(w, r, http.StatusBadRequest, resultData)
 			return
 		}
+		resultData["InputFormat"] = sourceInput.Description()
+		consolePrintf("입력 형식: %s\n", sourceInput.Description())
+		if !sourceInput.IsEQ() {
+			consolePrintf("경고: 입력 파일은 EQ가 아닌 주파수 응답 측정값으로 보입니다 (%s). 결과가 의도와 다를 수 있습니다.\n", sourceInput.Description())
+		}
+		if sourceProvenance.Generator != "" {
+			consolePrintf("경고: 입력 파일은 이미 %s 출력입니다 (원본: %s). VDSF 변환이 중복 적용됩니다.\n", sourceProvenance.Generator, sourceProvenance.Source)
+		}
+		if sourcePath == "" {
+			sourcePath = sourceFilename
+		}
+		target := eq.DetectTarget(sourcePath, sourceText)
 
-		sourceHarmanData, errHParse := parseAutoEQ(string(sourceHarmanBytes))
-		if errHParse != nil {
-			resultData["Error"] = fmt.Sprintf("입력 파일 파싱 오류: %v\n입력 파일 내용을 확인해주세요.", errHParse)
-			w.WriteHeader(http.StatusBadRequest)
-			indexTemplate.Execute(w, resultData)
+		selectedPipeline, opts, errSettings := parseConvertSettings(r)
+		if errSettings != nil {
+			resultData["Error"] = lang.Err(errSettings)
+			writeResponse(w, r, http.StatusBadRequest, resultData)
+			return
+		}
+		resultData["SelectedPipeline"] = selectedPipeline.Name
+		opts.Exports = true // 결과 화면에 내보내기 파일 표시
+
+		// --- Raw 측정값 (선택) ---
+		var rawMeasurement []eq.Point
+		var rawFilename, rawText string
+		rawFile, rawHandler, errRaw := r.FormFile("rawMeasurementFile")
+		switch {
+		case errRaw == nil:
+			rawBytes, errRawRead := readTextUpload(rawFile)
+			rawFile.Close()
+			if errRawRead != nil {
+				resultData["Error"] = lang.T("측정값 파일 읽기 오류: %v", errRawRead)
+				writeResponse(w, r, uploadErrorStatus(errRawRead), resultData)
+				return
+			}
+			rawFilename, rawText = rawHandler.Filename, string(rawBytes)
+		case errors.Is(errRaw, http.ErrMissingFile):
+			if rerun != nil {
+				rawFilename, rawText = rerun.RawFilename, rerun.Raw
+			}
+		default:
+			resultData["Error"] = lang.T("측정값 파일 업로드 오류: %v", errRaw)
+			writeResponse(w, r, http.StatusBadRequest, resultData)
 			return
 		}
+		if rawText != "" {
+			rawMeasurement, errRaw = eq.ParseMeasurement(rawText)
+			if errRaw != nil {
+				resultData["Error"] = lang.T("측정값 파일 파싱 오류: %v", errRaw)
+				writeResponse(w, r, http.StatusBadRequest, resultData)
+				return
+			}
+		}
 
 		// --- 계산 로직 ---
-		calculated_S_to_V_EQ := make(AutoEQData)
-		allFreqsMap := make(map[int]bool)
-		for freq := range sourceHarmanData {
-			allFreqsMap[freq] = true
-		}
-		for freq := range harmanToVdsfEQ {
-			allFreqsMap[freq] = true
-		}
-		var allFreqs []int
-		for freq := range allFreqsMap {
-			allFreqs = append(allFreqs, freq)
-		}
-		sort.Ints(allFreqs)
-		for _, freq := range allFreqs {
-			calculated_S_to_V_EQ[freq] = sourceHarmanData[freq] + harmanToVdsfEQ[freq]
-		}
-
-		// --- 스무딩 1단계 (이동 평균 사용) ---
-		smoothed_S_to_V_EQ := applyMovingAverageSmoothing(calculated_S_to_V_EQ, allFreqs, movingAverageWindow, smoothStartFreq)
-		fmt.Println("1차 스무딩 적용됨.")
-
-		// --- 결과 1 생성 (스무딩 후 NoPreamp) ---
-		result1EQ_NoPreamp := applyNoPreamp(smoothed_S_to_V_EQ, allFreqs)
-		filename1 := fmt.Sprintf("%s_AHTVC-By_MiFun.txt", sourceName)
-		result1Str := formatEQString(result1EQ_NoPreamp, allFreqs)
-		resultData["Filename1"] = filename1
-		resultData["Result1"] = result1Str
-
-		// --- 결과 2 생성 (스무딩 + X2 + 스무딩 + NoPreamp) ---
-		intermediateResult2EQ_withX2 := applyX2EQ(smoothed_S_to_V_EQ, x2EQPoints, allFreqs)
-		smoothed_IntermediateResult2EQ := applyMovingAverageSmoothing(intermediateResult2EQ_withX2, allFreqs, movingAverageWindow, smoothStartFreq)
-		fmt.Println("2차 스무딩 적용됨.")
-		result2EQ_NoPreamp := applyNoPreamp(smoothed_IntermediateResult2EQ, allFreqs)
-		filename2 := fmt.Sprintf("%s_AHTVCLr2-By_MiFun.txt", sourceName)
-		result2Str := formatEQString(result2EQ_NoPreamp, allFreqs)
-		resultData["Filename2"] = filename2
-		resultData["Result2"] = result2Str
+		input := convertInput{
+			Source:         sourceInput.Curve,
+			Channels:       sourceInput.Channels,
+			SourceFilename: sourceFilename,
+			SourcePath:     sourcePath,
+			Target:         target,
+			Raw:            rawMeasurement,
+		}
+		results, errConvert := convertSource(input, selectedPipeline, opts)
+		if errConvert != nil {
+			resultData["Error"] = lang.T("파이프라인 구성 오류: %v", errConvert)
+			writeResponse(w, r, http.StatusInternalServerError, resultData)
+			return
+		}
+		resultData["Results"] = localizeMetrics(lang, results)
+		// 옵션을 바꿀 때 파일을 다시 올리지 않도록 입력을 미리보기 세션에 보관
+		resultData["PreviewToken"] = activePreviews.Add(input)
+
+		// 변환 기록과 프리셋 저장 (실패해도 변환 결과는 그대로 표시)
+		entry := historyEntry{
+			Time:           time.Now(),
+			SourceFilename: sourceFilename,
+			Source:         sourceText,
+			SourcePath:     sourcePath,
+			RawFilename:    rawFilename,
+			Raw:            rawText,
+			Params:         formParams(r),
+		}
+		for _, result := range results {
+			entry.Outputs = append(entry.Outputs, historyOutput{Name: result.Name, Filename: result.Filename, Content: result.Content})
+		}
+		if entry, errHistory := activeHistory.Add(entry); errHistory != nil {
+			logPrintf("변환 기록 저장 오류: %v", errHistory)
+		} else if entry.ID != "" {
+			resultData["HistoryID"] = entry.ID
+		}
+		if presetName := strings.TrimSpace(r.FormValue("presetName")); presetName != "" {
+			if errPreset := activeHistory.SavePreset(preset{Name: presetName, Params: formParams(r)}); errPreset != nil {
+				resultData["Error"] = lang.T("프리셋 저장 오류: %v", errPreset)
+			} else {
+				resultData["SavedPreset"] = presetName
+			}
+		}
+		addHistoryData(resultData)
 	}
 
-	// 템플릿 렌더링
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	err := indexTemplate.Execute(w, resultData)
-	if err != nil {
-		log.Printf("템플릿 실행 오류: %v", err)
-		http.Error(w, "페이지 렌더링 오류", http.StatusInternalServerError)
-	}
+	writeResponse(w, r, http.StatusOK, resultData)
 }
 
-// --- Helper Functions ---
+// 변환 입력
+type convertInput struct {
+	Source         eq.Curve   // Harman 타겟 AutoEQ 곡선
+	SourceFilename string     // 원본 파일 이름 (출력 이름 및 헤더용)
+	SourcePath     string     // 기기 정보를 읽을 원본 경로 (비어 있으면 파일 이름 사용, 예: AutoEQ 저장소 상대 경로)
+	Target         string     // 추정된 입력 타겟
+	Raw            []eq.Point // Raw 측정값 (선택)
+	// Equalizer APO 설정의 채널별 곡선 (채널이 둘 이상이면 채널마다 따로 변환)
+	Channels []eq.ChannelCurve
+	Channel  string // 변환 중인 채널 (출력 이름과 헤더용)
+}
 
-// 상수 EQ 파싱
-func parseConstantEQ(content string) AutoEQData {
-	data, err := parseAutoEQ(content)
-	if err != nil {
-		log.Fatalf("상수 EQ 데이터 '%s...' 파싱 실패: %v", content[:min(30, len(content))], err)
-	}
-	return data
+// 변환 출력 옵션
+type convertOptions struct {
+	Format     eq.FormatOptions
+	Preamp     *eq.PreampOptions // nil 이면 파이프라인의 Preamp 설정 사용
+	LevelMatch bool              // 출력 간 체감 음량 (ITU-R 468) 맞춤
+	Exports    bool              // 플레이어별 내보내기 생성 (화면에 보여주거나 파일로 저장할 때만)
 }
 
-// AutoEQ 파일 파싱
-func parseAutoEQ(content string) (AutoEQData, error) {
-	data := make(AutoEQData)
-	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
-	graphicEqFound := false
-	lineNum := 0
-
-	for _, line := range lines {
-		lineNum++
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
-			continue
-		}
-
-		if strings.HasPrefix(line, "GraphicEQ:") {
-			graphicEqFound = true
-			pointsStr := strings.TrimPrefix(line, "GraphicEQ:")
-			pointsStr = strings.TrimSpace(pointsStr)
-			points := strings.Split(pointsStr, ";")
-			pointCount := 0
-			for pointIdx, point := range points {
-				point = strings.TrimSpace(point)
-				if point == "" {
-					if pointIdx == len(points)-1 {
-						continue
-					} else {
-						fmt.Printf("경고: Line %d, 비어있는 EQ 포인트 발견 (인덱스 %d).\n", lineNum, pointIdx)
-						continue
-					}
-				}
-				parts := strings.Fields(point)
-				if len(parts) != 2 {
-					fmt.Printf("경고: Line %d, 잘못된 포인트 형식 무시 (항목 %d개): '%s'\n", lineNum, len(parts), point)
-					continue
-				}
-				freq, errF := strconv.Atoi(parts[0])
-				gain, errG := strconv.ParseFloat(parts[1], 64)
-				if errF != nil || errG != nil {
-					fmt.Printf("경고: Line %d, 숫자 변환 오류 무시 ('%s'): %v, %v\n", lineNum, point, errF, errG)
-					continue
-				}
-				if math.IsNaN(gain) || math.IsInf(gain, 0) {
-					fmt.Printf("경고: Line %d, 잘못된 게인 값 (NaN or Inf) 무시 at freq %d\n", lineNum, freq)
-					continue
-				}
-				if freq <= 0 || freq > 30000 {
-					fmt.Printf("경고: Line %d, 비정상적인 주파수 값 무시: %d\n", lineNum, freq)
-					continue
-				}
-				data[freq] = gain
-				pointCount++
-			}
-			if pointCount == 0 && len(points) > 0 && strings.TrimSpace(points[0]) != "" {
-				return nil, fmt.Errorf("line %d: GraphicEQ 라인에서 유효한 포인트를 찾을 수 없음: '%s'", lineNum, line)
+// 선택한 파이프라인의 출력별로 결과 생성 (웹/폴더 감시 공용)
+func convertSource(in convertInput, selectedPipeline eq.PipelineConfig, opts convertOptions) ([]convertResult, error) {
+	if len(in.Channels) > 1 {
+		var results []convertResult
+		for _, ch := range in.Channels {
+			channelIn := in
+			channelIn.Source, channelIn.Channel, channelIn.Channels = ch.Curve, ch.Channel, nil
+			channelResults, err := convertSource(channelIn, selectedPipeline, opts)
+			if err != nil {
+				return nil, err
 			}
-			break
+			results = append(results, channelResults...)
 		}
+		return results, nil
 	}
 
-	if !graphicEqFound {
-		return nil, errors.New("'GraphicEQ:' 라인을 찾을 수 없음 (파일 형식을 확인하세요)")
+	sourcePath := in.SourcePath
+	if sourcePath == "" {
+		sourcePath = in.SourceFilename
 	}
-	if len(data) == 0 {
-		return nil, errors.New("파싱된 유효한 EQ 데이터 포인트가 없음")
+	device := eq.ParseDeviceInfo(sourcePath)
+	if in.Channel != "" {
+		device.Channel = in.Channel
 	}
-	return data, nil
-}
+	sourceName := device.FileStem()
+	// VDSF 타겟을 정확히 맞추는 EQ (품질 지표 기준)
+	idealEQ := eq.ApplyTarget(in.Source, eq.HarmanToVDSF)
 
-// Preamp 제거
-func applyNoPreamp(inputEQ AutoEQData, sortedFreqs []int) AutoEQData {
-	outputEQ := make(AutoEQData)
-	maxGain := -math.MaxFloat64
-	hasData := false
-
-	for _, freq := range sortedFreqs {
-		if gain, ok := inputEQ[freq]; ok {
-			if math.IsNaN(gain) || math.IsInf(gain, 0) {
-				fmt.Printf("경고: applyNoPreamp 입력에서 잘못된 게인 값 발견 (freq: %d). 0.0으로 처리.\n", freq)
-				gain = 0.0
-			}
-			outputEQ[freq] = gain
-			if gain > maxGain {
-				maxGain = gain
-			}
-			hasData = true
+	pipelineResults := make([]eq.Result, len(selectedPipeline.Outputs))
+	for i, output := range selectedPipeline.Outputs {
+		pipeline, err := output.Build()
+		if err != nil {
+			return nil, err
 		}
+		if opts.Preamp != nil {
+			pipeline.ReplacePreamp(*opts.Preamp)
+		}
+		pipelineResults[i] = pipeline.Run(in.Source)
+		consolePrintf("'%s' 출력 생성됨 (%s).\n", output.Name, pipelineResults[i].Params[0].Value)
 	}
 
-	if !hasData {
-		fmt.Println("applyNoPreamp 경고: 처리할 유효한 EQ 데이터가 없습니다.")
-		return outputEQ
-	}
-	if math.IsNaN(maxGain) || math.IsInf(maxGain, 0) {
-		fmt.Println("경고: 최대 게인 계산 불가 (NaN or Inf). Preamp 이동이 적용되지 않습니다.")
-		return outputEQ
-	}
-
-	shift := 0.0
-	if maxGain > 1e-9 {
-		shift = maxGain
+	// 출력 간 체감 음량 맞춤 (가장 작은 출력 기준으로 내림)
+	levelOffsets := make([]float64, len(pipelineResults))
+	if opts.LevelMatch && len(pipelineResults) > 1 {
+		curves := make([]eq.Curve, len(pipelineResults))
+		for i, result := range pipelineResults {
+			curves[i] = result.Curve
+		}
+		levelOffsets = eq.LevelMatch(curves)
+		for i := range pipelineResults {
+			result := &pipelineResults[i]
+			result.Curve = result.Curve.Offset(levelOffsets[i])
+			result.PreampShift -= levelOffsets[i]
+			result.Params = append(result.Params,
+				eq.Param{Key: "levelMatch", Value: "itu468"},
+				eq.Param{Key: "levelOffset", Value: strconv.FormatFloat(levelOffsets[i], 'f', 2, 64)})
+			consolePrintf("'%s' 음량 맞춤: %+.2f dB\n", selectedPipeline.Outputs[i].Name, levelOffsets[i])
+		}
 	}
 
-	for freq := range outputEQ {
-		newValue := outputEQ[freq] - shift
-		if math.IsNaN(newValue) || math.IsInf(newValue, 0) {
-			fmt.Printf("경고: Preamp 적용 중 잘못된 값 발생 (freq: %d). 0.0으로 대체.\n", freq)
-			outputEQ[freq] = 0.0
-		} else {
-			outputEQ[freq] = newValue
+	var results []convertResult
+	for i, output := range selectedPipeline.Outputs {
+		result := pipelineResults[i]
+		filename := sourceName + output.Suffix + ".txt"
+		name := output.Name
+		if in.Channel != "" {
+			name += " (" + in.Channel + ")"
 		}
-	}
-	return outputEQ
+		var exports []eq.ExportFile
+		if opts.Exports {
+			exports = eq.ExportAll(result.Curve, filename, strings.TrimSuffix(filename, ".txt"))
+		}
+		results = append(results, convertResult{
+			Name:     name,
+			Filename: filename,
+			Content: eq.FormatFile(result.Curve, opts.Format, eq.Provenance{
+				Source:      in.SourceFilename,
+				Device:      device,
+				Target:      in.Target,
+				Params:      result.Params,
+				PreampShift: result.PreampShift,
+			}),
+			Exports:      exports,
+			Metrics:      eq.ComputeMetrics(result.Curve.Offset(result.PreampShift), idealEQ, in.Raw),
+			LevelMatched: opts.LevelMatch && len(pipelineResults) > 1,
+			LevelOffset:  levelOffsets[i],
+			Curve:        result.Curve,
+		})
+	}
+	return results, nil
 }
 
-// 문자열 포맷
-func formatEQString(eqData AutoEQData, sortedFreqs []int) string {
-	var resultBuffer bytes.Buffer
-	resultBuffer.WriteString("GraphicEQ: ")
-	var eqPoints []string
-	for _, freq := range sortedFreqs {
-		if gain, ok := eqData[freq]; ok {
-			if math.IsNaN(gain) || math.IsInf(gain, 0) {
-				fmt.Printf("경고: 결과 포맷팅 중 잘못된 게인 값 발견 (freq: %d). 0.0으로 대체.\n", freq)
-				gain = 0.0
-			}
-			eqPoints = append(eqPoints, fmt.Sprintf("%d %.1f", freq, gain))
-		}
-	}
-	if len(eqPoints) > 0 {
-		resultBuffer.WriteString(strings.Join(eqPoints, "; "))
-	} else {
-		resultBuffer.Reset()
-		resultBuffer.WriteString("GraphicEQ: (No valid points)")
+// JSON 응답 요청 여부 (?format=json 또는 Accept 헤더)
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
 	}
-	return resultBuffer.String()
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
 }
 
-// 소스 이름 추출
-func extractSourceName(filename string) string {
-	name := strings.TrimSuffix(filename, ".txt")
-	patternsToRemove := []string{" Graphic Filters Harman", " Graphic Filters VDSF", " Graphic Filters", " target Harman", " target VDSF", " target", " (AVG)", " (Target)", "(L)", "(R)", " Harman", " VDSF"}
-	normalizedName := name
-	changed := true
-	for changed {
-		changed = false
-		currentName := normalizedName
-		for _, pattern := range patternsToRemove {
-			lowerPattern := strings.ToLower(pattern)
-			lowerName := strings.ToLower(currentName)
-			if strings.HasSuffix(lowerName, lowerPattern) {
-				normalizedName = strings.TrimSpace(currentName[:len(currentName)-len(pattern)])
-				changed = true
-				break
-			}
+// 결과 응답 (템플릿 렌더링 또는 JSON)
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, resultData map[string]interface{}) {
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(resultData); err != nil {
+			logPrintf("JSON 응답 오류: %v", err)
 		}
+		return
 	}
-	normalizedName = strings.TrimSpace(normalizedName)
-	if normalizedName == "" {
-		return "UnknownDevice"
+
+	// 템플릿 렌더링
+	var page bytes.Buffer
+	err := templateFor(indexTemplates, resultData).Execute(&page, resultData)
+	if err != nil {
+		logPrintf("템플릿 실행 오류: %v", err)
+		lang, _ := resultData["Lang"].(language)
+		http.Error(w, lang.T("페이지 렌더링 오류"), http.StatusInternalServerError)
+		return
 	}
-	lowerFinalName := strings.ToLower(normalizedName)
-	if lowerFinalName == "result" || lowerFinalName == "output" || lowerFinalName == "graphic" || lowerFinalName == "eq" {
-		parts := strings.Fields(name)
-		if len(parts) > 0 && strings.ToLower(parts[0]) != lowerFinalName {
-			return parts[0]
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	page.WriteTo(w)
+}
+
+// --- Helper Functions ---
+
+// 품질 지표의 대역 이름을 요청 언어로 번역 (화면과 JSON 응답 공용)
+func localizeMetrics(lang language, results []convertResult) []convertResult {
+	localized := append([]convertResult(nil), results...)
+	for i := range localized {
+		bands := append([]eq.BandDeviation(nil), localized[i].Metrics.Bands...)
+		for j := range bands {
+			bands[j].Name = lang.T(bands[j].Name)
 		}
-		return "UnknownDevice"
+		localized[i].Metrics.Bands = bands
 	}
-	return normalizedName
+	return localized
+}
+
+// 소스 이름 추출 (출력 파일 이름용 기기 이름, 알 수 없으면 UnknownDevice)
+func extractSourceName(filename string) string {
+	return eq.ParseDeviceInfo(filename).FileStem()
 }
 
 // 브라우저 열기
@@ -473,7 +812,7 @@ func openBrowser(url string) error {
 			}
 		}
 		if !found {
-			return errors.New("지원되는 브라우저 열기 명령을 찾을 수 없음")
+			return errorf("지원되는 브라우저 열기 명령을 찾을 수 없음")
 		}
 	}
 	command := exec.Command(cmd, args...)
@@ -482,158 +821,7 @@ func openBrowser(url string) error {
 	command.Stderr = nil
 	err := command.Start()
 	if err != nil {
-		return fmt.Errorf("'%s %s' 실행 오류: %w", cmd, strings.Join(args, " "), err)
+		return errorf("'%s %s' 실행 오류: %w", cmd, strings.Join(args, " "), err)
 	}
 	return nil
 }
-
-// X2 EQ 적용 함수
-func applyX2EQ(baseEQ AutoEQData, x2EQ []eqPoint, allFreqs []int) AutoEQData {
-	resultEQ := make(AutoEQData)
-	for freq, gain := range baseEQ {
-		resultEQ[freq] = gain
-	}
-
-	if len(x2EQ) == 0 {
-		return resultEQ
-	}
-
-	for _, targetFreq := range allFreqs {
-		targetFreqFloat := float64(targetFreq)
-		x2GainToAdd := 0.0
-
-		if targetFreq < x2EQ[0].Freq {
-			x2GainToAdd = x2EQ[0].Gain
-		} else if targetFreq > x2EQ[len(x2EQ)-1].Freq {
-			x2GainToAdd = x2EQ[len(x2EQ)-1].Gain
-		} else {
-			for i := 0; i < len(x2EQ)-1; i++ {
-				lowerPoint := x2EQ[i]
-				upperPoint := x2EQ[i+1]
-				if targetFreq == lowerPoint.Freq {
-					x2GainToAdd = lowerPoint.Gain
-					break
-				}
-				if targetFreq == upperPoint.Freq {
-					x2GainToAdd = upperPoint.Gain
-					break
-				}
-				if targetFreq > lowerPoint.Freq && targetFreq < upperPoint.Freq {
-					logTarget := math.Log10(targetFreqFloat)
-					logLower := math.Log10(float64(lowerPoint.Freq))
-					logUpper := math.Log10(float64(upperPoint.Freq))
-					if logUpper-logLower < 1e-9 {
-						x2GainToAdd = lowerPoint.Gain
-					} else {
-						proportion := (logTarget - logLower) / (logUpper - logLower)
-						x2GainToAdd = lowerPoint.Gain + proportion*(upperPoint.Gain-lowerPoint.Gain)
-					}
-					break
-				}
-			}
-			if x2GainToAdd == 0 && targetFreq == x2EQ[len(x2EQ)-1].Freq {
-				x2GainToAdd = x2EQ[len(x2EQ)-1].Gain
-			}
-		}
-
-		if baseGain, ok := resultEQ[targetFreq]; ok {
-			newGain := baseGain + x2GainToAdd
-			if math.IsNaN(newGain) || math.IsInf(newGain, 0) {
-				fmt.Printf("경고: applyX2EQ 적용 중 잘못된 값 발생 (freq: %d). 원래 값 유지.\n", targetFreq)
-			} else {
-				resultEQ[targetFreq] = newGain
-			}
-		}
-	}
-	return resultEQ
-}
-
-// 이동 평균 스무딩 함수
-func applyMovingAverageSmoothing(inputEQ AutoEQData, sortedFreqs []int, windowSize int, startFreq float64) AutoEQData {
-	outputEQ := make(AutoEQData)
-	for freq, gain := range inputEQ {
-		outputEQ[freq] = gain
-	}
-
-	if windowSize <= 1 || windowSize%2 == 0 {
-		fmt.Printf("경고: 이동 평균 윈도우 크기(%d)는 1보다 큰 홀수여야 합니다. 스무딩을 건너<0xEB><0x9C><0x84>니다.\n", windowSize)
-		return outputEQ
-	}
-	if len(sortedFreqs) < windowSize {
-		fmt.Printf("경고: 데이터 포인트 개수(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너<0xEB><0x9C><0x84>니다.\n", len(sortedFreqs), windowSize)
-		return outputEQ
-	}
-
-	halfWindow := windowSize / 2
-	startIndex := -1
-	for i, freq := range sortedFreqs {
-		if float64(freq) >= startFreq {
-			startIndex = i
-			break
-		}
-	}
-
-	if startIndex == -1 || len(sortedFreqs)-startIndex < windowSize {
-		if startIndex == -1 {
-			fmt.Printf("%f Hz 이상 포인트를 찾지 못해 스무딩을 건너<0xEB><0x9C><0x84>니다.\n", startFreq)
-		} else {
-			fmt.Printf("%f Hz 이상 데이터 포인트(%d)가 윈도우 크기(%d)보다 작아 스무딩을 건너<0xEB><0x9C><0x84>니다.\n", startFreq, len(sortedFreqs)-startIndex, windowSize)
-		}
-		return outputEQ
-	}
-
-	fmt.Printf("%f Hz 이상 고음역대에 이동 평균 스무딩 적용 (Window=%d)...\n", startFreq, windowSize)
-
-	tempGains := make([]float64, len(sortedFreqs))
-	for i, freq := range sortedFreqs {
-		if gain, ok := inputEQ[freq]; ok {
-			tempGains[i] = gain
-		} else {
-			tempGains[i] = 0.0
-		}
-	}
-
-	// 이동 평균 계산 (가장자리 처리는 원본 유지 방식)
-	smoothedOutputGains := make([]float64, len(sortedFreqs))
-	copy(smoothedOutputGains, tempGains) // 원본으로 초기화
-
-	for i := startIndex + halfWindow; i < len(sortedFreqs)-halfWindow; i++ {
-		sum := 0.0
-		count := 0
-		for j := i - halfWindow; j <= i+halfWindow; j++ {
-			if j >= 0 && j < len(tempGains) {
-				if !math.IsNaN(tempGains[j]) && !math.IsInf(tempGains[j], 0) {
-					sum += tempGains[j]
-					count++
-				} else {
-					fmt.Printf("경고: 스무딩 계산 중 잘못된 값 발견 (index: %d). 건너<0xEB><0x9C><0x84>뜁니다.\n", j)
-				}
-			}
-		}
-		if count > 0 {
-			avg := sum / float64(count)
-			if !math.IsNaN(avg) && !math.IsInf(avg, 0) {
-				smoothedOutputGains[i] = avg // 계산된 평균값으로 업데이트
-			} else {
-				fmt.Printf("경고: 스무딩 평균 계산 결과가 잘못됨 (index: %d). 원래 값 유지.\n", i)
-			}
-		} else {
-			fmt.Printf("경고: 스무딩 윈도우 내 유효 값 없음 (index: %d). 원래 값 유지.\n", i)
-		}
-	}
-
-	// 스무딩된 결과를 원래의 map 구조로 변환
-	for i, freq := range sortedFreqs {
-		outputEQ[freq] = smoothedOutputGains[i]
-	}
-
-	return outputEQ
-}
-
-// min 함수
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ahtvc/eq"
+)
+
+var updateGolden = flag.Bool("update", false, "testdata/golden 의 기대 출력 파일을 다시 생성")
+
+// 기본 파이프라인으로 AHTVC_Core 파일을 변환한 출력 (웹 JSON 응답) 이 골든 파일과 같은지 확인
+func TestHandleConvertGolden(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField(csrfFieldName, testCSRFToken)
+	fw, err := mw.CreateFormFile("sourceHarmanFile", "AHTVC_Core-By_MiFun.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(readCoreEQ(t))
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/?format=json", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: testCSRFToken})
+
+	silenceLogs(t)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		Error   string
+		Results []convertResult
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Results) != len(activePipelines.Pipelines[0].Outputs) {
+		t.Fatalf("결과 %d개, want %d", len(response.Results), len(activePipelines.Pipelines[0].Outputs))
+	}
+
+	files := map[string]string{}
+	for _, result := range response.Results {
+		files[result.Filename] = result.Content
+		for _, export := range result.Exports {
+			files[export.Filename] = export.Content
+		}
+	}
+	for filename, content := range files {
+		golden := filepath.Join("testdata", "golden", filename)
+		if *updateGolden {
+			if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Errorf("골든 파일 읽기 오류 (go test -run Golden -update 로 생성): %v", err)
+			continue
+		}
+		if content != string(want) {
+			t.Errorf("%s 출력이 골든 파일과 다름 (의도한 변경이면 -update)\ngot:\n%s\nwant:\n%s", filename, content, want)
+		}
+	}
+}
+
+// 내보내기 파일은 Exports 옵션을 켠 경우에만 생성 (폴더 감시 -exports, 웹 결과 화면)
+func TestConvertSourceExportsOptIn(t *testing.T) {
+	silenceLogs(t)
+	source, err := eq.Parse(string(readCoreEQ(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := convertInput{Source: source, SourceFilename: "AHTVC_Core-By_MiFun.txt"}
+	pipeline := activePipelines.Pipelines[0]
+
+	results, err := convertSource(in, pipeline, convertOptions{Format: eq.DefaultFormatOptions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if len(result.Exports) != 0 {
+			t.Errorf("%s: 옵션 없이 내보내기 %d개 생성", result.Name, len(result.Exports))
+		}
+	}
+
+	results, err = convertSource(in, pipeline, convertOptions{Format: eq.DefaultFormatOptions, Exports: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if len(result.Exports) != len(eq.ExportProfiles) {
+			t.Errorf("%s: 내보내기 %d개, want %d", result.Name, len(result.Exports), len(eq.ExportProfiles))
+		}
+	}
+}
+
+// PipeWire/CamillaDSP 파이프라인 설정도 다른 내보내기와 같이 -exports 일 때만 폴더에 저장
+func TestWriteConvertResultsPipelineProfiles(t *testing.T) {
+	silenceLogs(t)
+	source, err := eq.Parse(string(readCoreEQ(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := convertInput{Source: source, SourceFilename: "AHTVC_Core-By_MiFun.txt"}
+	for _, exports := range []bool{false, true} {
+		results, err := convertSource(in, activePipelines.Pipelines[0], convertOptions{Format: eq.DefaultFormatOptions, Exports: exports})
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs, err := writeConvertResults(t.TempDir(), results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pipeWire, camilla int
+		for _, name := range outputs {
+			if strings.HasSuffix(name, "_PipeWire.conf") {
+				pipeWire++
+			}
+			if strings.HasSuffix(name, "_CamillaDSP_Pipeline.yml") {
+				camilla++
+			}
+		}
+		want := 0
+		if exports {
+			want = len(results)
+		}
+		if pipeWire != want || camilla != want {
+			t.Errorf("exports=%v: PipeWire %d개, CamillaDSP 파이프라인 %d개, want %d개", exports, pipeWire, camilla, want)
+		}
+	}
+}
+
+func TestExtractSourceName(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"Sennheiser HD 650 GraphicEQ.txt", "Sennheiser HD 650"},
+		{"Moondrop Blessing 2 (L).csv", "Moondrop Blessing 2 (L)"},
+		{"AHTVC_Core-By_MiFun.txt", "AHTVC_Core-By_MiFun"},
+		{`C:\Users\me\Downloads\Sony WH-1000XM4 ParametricEQ.txt`, "Sony WH-1000XM4"},
+		{"result.txt", "UnknownDevice"},
+		{"", "UnknownDevice"},
+		{"/", "UnknownDevice"},
+		{".hidden.txt", "hidden"},
+		{"..", "UnknownDevice"},
+	}
+	for _, tt := range tests {
+		if got := extractSourceName(tt.filename); got != tt.want {
+			t.Errorf("extractSourceName(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func FuzzExtractSourceName(f *testing.F) {
+	for _, seed := range []string{
+		"Sennheiser HD 650 GraphicEQ.txt",
+		"results/crinacle/711 in-ear/Moondrop Blessing 2 (L)/Moondrop Blessing 2 (L).csv",
+		`..\..\evil (R).txt`,
+		"(L)",
+		" GraphicEQ",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, filename string) {
+		name := extractSourceName(filename)
+		if name == "" {
+			t.Fatal("빈 이름")
+		}
+		// 출력 파일 이름에 쓰이므로 경로 구분자가 있으면 안 됨
+		if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			t.Fatalf("extractSourceName(%q) = %q: 파일 이름으로 쓸 수 없음", filename, name)
+		}
+	})
+}
+
+// 테스트 중 라이브러리 경고 출력 끄기
+func silenceLogs(tb testing.TB) {
+	tb.Helper()
+	saved := eq.Logf
+	eq.Logf = nil
+	tb.Cleanup(func() { eq.Logf = saved })
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ahtvc/eq"
+)
+
+// 요청 폼에서 파이프라인과 변환 옵션 읽기 (변환/실시간 미리보기 공용)
+func parseConvertSettings(r *http.Request) (eq.PipelineConfig, convertOptions, error) {
+	var opts convertOptions
+	selected, found := activePipelines.Find(r.FormValue("pipeline"))
+	if !found {
+		return selected, opts, errorf("알 수 없는 파이프라인: '%s'", r.FormValue("pipeline"))
+	}
+	var err error
+	if opts.Format, err = parseFormatOptions(r); err != nil {
+		return selected, opts, errorf("출력 옵션 오류: %v", err)
+	}
+	if opts.Preamp, err = parsePreampOptions(r); err != nil {
+		return selected, opts, errorf("Preamp 옵션 오류: %v", err)
+	}
+	loudness, err := parseLoudnessOptions(r.FormValue("loudnessRef"), r.FormValue("loudnessLevels"))
+	if err != nil {
+		return selected, opts, errorf("등청감 보정 옵션 오류: %v", err)
+	}
+	if loudness != nil {
+		selected = selected.LoudnessFamily(loudness.Reference, loudness.Levels)
+	}
+	opts.LevelMatch = r.FormValue("levelMatch") != ""
+	return selected, opts, nil
+}
+
+// 요청 폼에서 출력 포맷 옵션 읽기 (비어있는 값은 기본값 사용)
+func parseFormatOptions(r *http.Request) (eq.FormatOptions, error) {
+	opts := eq.DefaultFormatOptions
+	intField := func(name string, target *int, minValue, maxValue int) error {
+		value := strings.TrimSpace(r.FormValue(name))
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minValue || n > maxValue {
+			return errorf("'%s' 값이 올바르지 않습니다 (%d~%d 정수): '%s'", name, minValue, maxValue, value)
+		}
+		*target = n
+		return nil
+	}
+	if err := intField("precision", &opts.Precision, 0, 3); err != nil {
+		return opts, err
+	}
+	if err := intField("minFreq", &opts.MinFreq, 0, 30000); err != nil {
+		return opts, err
+	}
+	if err := intField("maxFreq", &opts.MaxFreq, 0, 30000); err != nil {
+		return opts, err
+	}
+	if err := intField("maxPoints", &opts.MaxPoints, 0, 10000); err != nil {
+		return opts, err
+	}
+	if value := strings.TrimSpace(r.FormValue("quantizeStep")); value != "" {
+		step, err := strconv.ParseFloat(value, 64)
+		if err != nil || step < 0 || step > 6 || math.IsNaN(step) {
+			return opts, errorf("'quantizeStep' 값이 올바르지 않습니다 (0~6 dB): '%s'", value)
+		}
+		opts.QuantizeStep = step
+	}
+	if opts.MaxFreq > 0 && opts.MinFreq >= opts.MaxFreq {
+		return opts, errorf("주파수 범위가 올바르지 않습니다 (minFreq %d >= maxFreq %d)", opts.MinFreq, opts.MaxFreq)
+	}
+	if opts.MaxPoints == 1 {
+		return opts, errorf("'maxPoints'는 0(제한 없음) 또는 2 이상이어야 합니다")
+	}
+	opts.SeparatePreamp = r.FormValue("separatePreamp") != ""
+	return opts, nil
+}
+
+// 요청 폼에서 Preamp 옵션 읽기 (둘 다 비어 있으면 nil: 파이프라인 설정 사용)
+func parsePreampOptions(r *http.Request) (*eq.PreampOptions, error) {
+	mode := strings.TrimSpace(r.FormValue("preampMode"))
+	headroom := strings.TrimSpace(r.FormValue("preampHeadroom"))
+	if mode == "" && headroom == "" {
+		return nil, nil
+	}
+	var opts eq.PreampOptions
+	var err error
+	if opts.Mode, err = eq.ParsePreampMode(mode); err != nil {
+		return nil, err
+	}
+	if headroom != "" {
+		if opts.Headroom, err = strconv.ParseFloat(headroom, 64); err != nil {
+			return nil, errorf("'preampHeadroom' 값이 올바르지 않습니다: '%s'", headroom)
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+// 등청감 보정 옵션 (청취 음량 목록이 비어 있으면 사용 안 함)
+type loudnessOptions struct {
+	Reference float64   // 기준 음량 (phon)
+	Levels    []float64 // 청취 음량 목록 (phon), 각 음량마다 출력 생성
+}
+
+// 기준 음량과 쉼표로 구분된 청취 음량 목록 읽기 (목록이 비어 있으면 nil)
+func parseLoudnessOptions(reference, levels string) (*loudnessOptions, error) {
+	reference, levels = strings.TrimSpace(reference), strings.TrimSpace(levels)
+	if levels == "" {
+		return nil, nil
+	}
+	opts := &loudnessOptions{Reference: eq.DefaultReferencePhon}
+	if reference != "" {
+		ref, err := strconv.ParseFloat(reference, 64)
+		if err != nil {
+			return nil, errorf("기준 음량 값이 올바르지 않습니다: '%s'", reference)
+		}
+		opts.Reference = ref
+	}
+	if err := eq.ValidatePhon(opts.Reference); err != nil {
+		return nil, err
+	}
+	seen := make(map[float64]bool)
+	for _, field := range strings.Split(levels, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		level, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, errorf("청취 음량 값이 올바르지 않습니다: '%s'", field)
+		}
+		if err := eq.ValidatePhon(level); err != nil {
+			return nil, err
+		}
+		if !seen[level] {
+			seen[level] = true
+			opts.Levels = append(opts.Levels, level)
+		}
+	}
+	if len(opts.Levels) == 0 {
+		return nil, errorf("청취 음량 목록이 비어 있습니다: '%s'", levels)
+	}
+	return opts, nil
+}
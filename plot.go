@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"math"
+
+	"ahtvc/eq"
+)
+
+// 그래프 크기 및 여백
+const (
+	plotWidth   = 760
+	plotHeight  = 320
+	plotMargin  = 40
+	plotMinFreq = 20.0
+	plotMaxFreq = 20000.0
+)
+
+// 그래프 색상 (시리즈 순서대로 사용)
+var plotColors = []string{"#007bff", "#D8000C", "#28a745", "#fd7e14", "#6f42c1"}
+
+// 그래프에 그릴 곡선
+type plotSeries struct {
+	Name  string
+	Freqs []float64
+	Gains []float64
+}
+
+// EQ 곡선을 그래프 곡선으로 변환
+func seriesFromEQ(name string, data eq.Curve) plotSeries {
+	s := plotSeries{Name: name}
+	for _, p := range data {
+		s.Freqs = append(s.Freqs, p.Freq)
+		s.Gains = append(s.Gains, p.Gain)
+	}
+	return s
+}
+
+// 주파수 응답 오버레이 SVG 생성 (X축 로그 스케일)
+func renderOverlaySVG(series []plotSeries) template.HTML {
+	minGain, maxGain := math.MaxFloat64, -math.MaxFloat64
+	for _, s := range series {
+		for _, gain := range s.Gains {
+			minGain = math.Min(minGain, gain)
+			maxGain = math.Max(maxGain, gain)
+		}
+	}
+	if minGain > maxGain {
+		return ""
+	}
+	minGain = math.Floor(minGain) - 1
+	maxGain = math.Ceil(maxGain) + 1
+
+	innerW := float64(plotWidth - 2*plotMargin)
+	innerH := float64(plotHeight - 2*plotMargin)
+	logMin, logMax := math.Log10(plotMinFreq), math.Log10(plotMaxFreq)
+	xOf := func(freq float64) float64 {
+		return plotMargin + (math.Log10(freq)-logMin)/(logMax-logMin)*innerW
+	}
+	yOf := func(gain float64) float64 {
+		return plotMargin + (maxGain-gain)/(maxGain-minGain)*innerH
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`, plotWidth, plotHeight, plotWidth, plotHeight)
+	fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%.0f" height="%.0f" fill="#fff" stroke="#ccc"/>`, plotMargin, plotMargin, innerW, innerH)
+
+	// 주파수 격자
+	for _, freq := range []float64{20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 20000} {
+		x := xOf(freq)
+		fmt.Fprintf(&buf, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.0f" stroke="#eee"/>`, x, plotMargin, x, plotMargin+innerH)
+		label := fmt.Sprintf("%.0f", freq)
+		if freq >= 1000 {
+			label = fmt.Sprintf("%.0fk", freq/1000)
+		}
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%.0f" text-anchor="middle" fill="#555">%s</text>`, x, plotMargin+innerH+14, label)
+	}
+
+	// 게인 격자
+	step := math.Max(1, math.Ceil((maxGain-minGain)/8))
+	for gain := math.Ceil(minGain/step) * step; gain <= maxGain; gain += step {
+		y := yOf(gain)
+		stroke := "#eee"
+		if gain == 0 {
+			stroke = "#999"
+		}
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%.1f" x2="%.0f" y2="%.1f" stroke="%s"/>`, plotMargin, y, plotMargin+innerW, y, stroke)
+		fmt.Fprintf(&buf, `<text x="%d" y="%.1f" text-anchor="end" fill="#555">%.0f</text>`, plotMargin-4, y+3, gain)
+	}
+
+	// 곡선 및 범례
+	for i, s := range series {
+		color := plotColors[i%len(plotColors)]
+		buf.WriteString(`<polyline fill="none" stroke-width="1.5" stroke="` + color + `" points="`)
+		for j, freq := range s.Freqs {
+			if freq < plotMinFreq || freq > plotMaxFreq {
+				continue
+			}
+			fmt.Fprintf(&buf, "%.1f,%.1f ", xOf(freq), yOf(s.Gains[j]))
+		}
+		buf.WriteString(`"/>`)
+		legendY := plotMargin + 12 + i*14
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"/>`, plotMargin+8, legendY-3, plotMargin+24, legendY-3, color)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" fill="#333">%s</text>`, plotMargin+28, legendY, template.HTMLEscapeString(s.Name))
+	}
+	fmt.Fprintf(&buf, `<text x="%d" y="%d" fill="#555">dB</text>`, 4, plotMargin-8)
+	buf.WriteString(`</svg>`)
+	return template.HTML(buf.String())
+}
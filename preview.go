@@ -0,0 +1,282 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// 실시간 미리보기 세션 제한
+const (
+	previewSessionTTL  = 30 * time.Minute // 마지막 사용 후 세션 유지 시간
+	maxPreviewSessions = 64               // 보관할 최대 세션 수 (넘으면 가장 오래 쓰지 않은 세션 삭제)
+	previewTokenBytes  = 16
+	previewKeepAlive   = 15 * time.Second // SSE 연결 유지용 주석 전송 간격
+	previewRetryMillis = 3000             // EventSource 재연결 대기 시간
+	previewTokenField  = "previewToken"
+)
+
+// 미리보기 세션 오류
+var errPreviewExpired = errorf("미리보기 세션이 만료되었습니다. 파일을 다시 변환해주세요")
+
+// 미리보기 세션 (업로드한 입력과 SSE 구독자)
+type previewSession struct {
+	input       convertInput
+	lastUsed    time.Time
+	subscribers map[chan []byte]struct{}
+}
+
+// 미리보기 세션 저장소 (세션 토큰 -> 입력, 메모리에만 보관)
+type previewStore struct {
+	mu       sync.Mutex
+	sessions map[string]*previewSession
+	now      func() time.Time
+}
+
+func newPreviewStore() *previewStore {
+	return &previewStore{sessions: make(map[string]*previewSession), now: time.Now}
+}
+
+// 서버 실행 중 미리보기 세션
+var activePreviews = newPreviewStore()
+
+// 입력을 보관하고 세션 토큰 반환
+func (s *previewStore) Add(in convertInput) string {
+	buf := make([]byte, previewTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("미리보기 토큰 생성 실패: %v", err))
+	}
+	token := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for t, session := range s.sessions {
+		if now.Sub(session.lastUsed) > previewSessionTTL {
+			s.removeLocked(t)
+		}
+	}
+	for len(s.sessions) >= maxPreviewSessions {
+		oldest := ""
+		for t, session := range s.sessions {
+			if oldest == "" || session.lastUsed.Before(s.sessions[oldest].lastUsed) {
+				oldest = t
+			}
+		}
+		s.removeLocked(oldest)
+	}
+	s.sessions[token] = &previewSession{input: in, lastUsed: now, subscribers: make(map[chan []byte]struct{})}
+	return token
+}
+
+// 세션 입력 조회 (사용 시각 갱신)
+func (s *previewStore) Input(token string) (convertInput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, err := s.sessionLocked(token)
+	if err != nil {
+		return convertInput{}, err
+	}
+	return session.input, nil
+}
+
+// 세션 업데이트 구독 (채널은 세션이 삭제되면 닫힘, 반환한 함수로 구독 해제)
+func (s *previewStore) Subscribe(token string) (<-chan []byte, func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, err := s.sessionLocked(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	ch := make(chan []byte, 1)
+	session.subscribers[ch] = struct{}{}
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := session.subscribers[ch]; ok {
+			delete(session.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel, nil
+}
+
+// 세션 구독자에게 업데이트 전송 (느린 구독자는 최신 업데이트만 받음)
+func (s *previewStore) Publish(token string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[token]
+	if !ok {
+		return
+	}
+	for ch := range session.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- data
+	}
+}
+
+// 모든 세션 삭제 (서버 종료 시 SSE 연결을 끝내기 위해 사용)
+func (s *previewStore) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for token := range s.sessions {
+		s.removeLocked(token)
+	}
+}
+
+func (s *previewStore) sessionLocked(token string) (*previewSession, error) {
+	session, ok := s.sessions[token]
+	if !ok {
+		return nil, errPreviewExpired
+	}
+	now := s.now()
+	if now.Sub(session.lastUsed) > previewSessionTTL {
+		s.removeLocked(token)
+		return nil, errPreviewExpired
+	}
+	session.lastUsed = now
+	return session, nil
+}
+
+func (s *previewStore) removeLocked(token string) {
+	session, ok := s.sessions[token]
+	if !ok {
+		return
+	}
+	for ch := range session.subscribers {
+		delete(session.subscribers, ch)
+		close(ch)
+	}
+	delete(s.sessions, token)
+}
+
+// 미리보기 결과 (출력 텍스트와 오버레이 그래프)
+type previewUpdate struct {
+	Results []previewResult `json:"results,omitempty"`
+	Plot    string          `json:"plot,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type previewResult struct {
+	Name     string `json:"name"`
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// POST /preview: 세션의 입력으로 파이프라인을 다시 계산해 응답하고 SSE 구독자에게도 전송
+func handlePreview(w http.ResponseWriter, r *http.Request) {
+	lang := negotiateLang(w, r)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writePreviewJSON(w, http.StatusMethodNotAllowed, previewUpdate{Error: lang.T("POST 요청만 지원합니다")})
+		return
+	}
+	if status, err := prepareUpload(w, r); err != nil {
+		writePreviewJSON(w, status, previewUpdate{Error: lang.T("업로드 거부: %v", err)})
+		return
+	}
+	token := r.FormValue(previewTokenField)
+	input, err := activePreviews.Input(token)
+	if err != nil {
+		writePreviewJSON(w, http.StatusNotFound, previewUpdate{Error: lang.Err(err)})
+		return
+	}
+	selectedPipeline, opts, err := parseConvertSettings(r)
+	if err != nil {
+		writePreviewJSON(w, http.StatusBadRequest, previewUpdate{Error: lang.Err(err)})
+		return
+	}
+
+	results, err := convertSource(input, selectedPipeline, opts)
+	if err != nil {
+		writePreviewJSON(w, http.StatusInternalServerError, previewUpdate{Error: lang.T("파이프라인 구성 오류: %v", err)})
+		return
+	}
+	update := previewUpdate{}
+	series := make([]plotSeries, len(results))
+	for i, result := range results {
+		update.Results = append(update.Results, previewResult{Name: result.Name, Filename: result.Filename, Content: result.Content})
+		series[i] = seriesFromEQ(result.Name, result.Curve)
+	}
+	update.Plot = string(renderOverlaySVG(series))
+
+	data, err := json.Marshal(update)
+	if err != nil {
+		logPrintf("미리보기 JSON 오류: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	activePreviews.Publish(token, data)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
+
+// GET /preview/events?previewToken=...: 미리보기 업데이트 SSE 스트림
+// 서버의 WriteTimeout 이 긴 연결을 끊지 않도록 쓰기 전마다 쓰기 기한을 늘립니다.
+func handlePreviewEvents(w http.ResponseWriter, r *http.Request) {
+	lang := negotiateLang(w, r)
+	updates, cancel, err := activePreviews.Subscribe(r.URL.Query().Get(previewTokenField))
+	if err != nil {
+		http.Error(w, lang.Err(err), http.StatusNotFound)
+		return
+	}
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	send := func(event string) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return false
+		}
+		if _, err := fmt.Fprint(w, event); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if !send(fmt.Sprintf("retry: %d\n\n", previewRetryMillis)) {
+		return
+	}
+
+	keepAlive := time.NewTicker(previewKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-updates:
+			if !ok {
+				// 세션 만료 또는 서버 종료: 클라이언트가 다시 연결하지 않도록 알림
+				send("event: expired\ndata: {}\n\n")
+				return
+			}
+			if !send("event: update\ndata: " + string(data) + "\n\n") {
+				return
+			}
+		case <-keepAlive.C:
+			if !send(": keepalive\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// 미리보기 JSON 응답
+func writePreviewJSON(w http.ResponseWriter, status int, update previewUpdate) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(update); err != nil {
+		logPrintf("미리보기 JSON 오류: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreviewStoreExpiry(t *testing.T) {
+	store := newPreviewStore()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	token := store.Add(convertInput{SourceFilename: "a.txt"})
+	if in, err := store.Input(token); err != nil || in.SourceFilename != "a.txt" {
+		t.Fatalf("Input() = %+v, %v", in, err)
+	}
+	updates, cancel, err := store.Subscribe(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	store.Publish(token, []byte("1"))
+	store.Publish(token, []byte("2"))
+	if got := string(<-updates); got != "2" {
+		t.Errorf("느린 구독자가 받은 업데이트 = %q, want 최신 값 2", got)
+	}
+
+	now = now.Add(previewSessionTTL + time.Second)
+	if _, err := store.Input(token); !errors.Is(err, errPreviewExpired) {
+		t.Errorf("만료된 세션 Input() 오류 = %v", err)
+	}
+	if _, ok := <-updates; ok {
+		t.Error("만료된 세션의 구독 채널이 닫히지 않음")
+	}
+
+	// 최대 개수를 넘으면 가장 오래 쓰지 않은 세션부터 삭제
+	first := store.Add(convertInput{})
+	for i := 0; i < maxPreviewSessions; i++ {
+		now = now.Add(time.Second)
+		store.Add(convertInput{})
+	}
+	if _, err := store.Input(first); err == nil {
+		t.Error("가장 오래된 세션이 삭제되지 않음")
+	}
+	if len(store.sessions) != maxPreviewSessions {
+		t.Errorf("세션 %d개, want %d", len(store.sessions), maxPreviewSessions)
+	}
+}
+
+// 변환 후 받은 토큰으로 옵션만 바꿔 다시 계산하고, SSE 스트림이 WriteTimeout 이 지나도 업데이트를 받는지 확인
+func TestPreviewLiveUpdate(t *testing.T) {
+	silenceLogs(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleConvert)
+	mux.HandleFunc("/preview", handlePreview)
+	mux.HandleFunc("/preview/events", handlePreviewEvents)
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	post := func(path string, fields map[string]string, source []byte) *http.Response {
+		t.Helper()
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField(csrfFieldName, testCSRFToken)
+		for name, value := range fields {
+			mw.WriteField(name, value)
+		}
+		if source != nil {
+			fw, _ := mw.CreateFormFile("sourceHarmanFile", "AHTVC_Core-By_MiFun.txt")
+			fw.Write(source)
+		}
+		mw.Close()
+		req, _ := http.NewRequest(http.MethodPost, server.URL+path, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: testCSRFToken})
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := post("/?format=json", nil, readCoreEQ(t))
+	var converted struct {
+		PreviewToken string
+		Results      []convertResult
+	}
+	json.NewDecoder(resp.Body).Decode(&converted)
+	resp.Body.Close()
+	if converted.PreviewToken == "" || len(converted.Results) == 0 {
+		t.Fatalf("변환 응답에 미리보기 토큰 없음: %+v", converted)
+	}
+
+	events, err := http.Get(server.URL + "/preview/events?previewToken=" + converted.PreviewToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Body.Close()
+	if ct := events.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	stream := bufio.NewReader(events.Body)
+	if line, _ := stream.ReadString('\n'); !strings.HasPrefix(line, "retry:") {
+		t.Fatalf("첫 이벤트 = %q", line)
+	}
+	time.Sleep(2 * server.Config.WriteTimeout)
+
+	resp = post("/preview", map[string]string{previewTokenField: converted.PreviewToken, "precision": "3"}, nil)
+	var update previewUpdate
+	json.NewDecoder(resp.Body).Decode(&update)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(update.Results) != len(converted.Results) || !strings.HasPrefix(update.Plot, "<svg") {
+		t.Fatalf("미리보기 응답 %d: %+v", resp.StatusCode, update)
+	}
+	if update.Results[0].Content == converted.Results[0].Content || !strings.Contains(update.Results[0].Content, ".000") {
+		t.Errorf("precision=3 이 미리보기에 반영되지 않음:\n%s", update.Results[0].Content)
+	}
+
+	var event, data string
+	for data == "" {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("SSE 읽기 오류: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	var pushed previewUpdate
+	if err := json.Unmarshal([]byte(data), &pushed); err != nil || event != "update" {
+		t.Fatalf("SSE 이벤트 %q: %v", event, err)
+	}
+	if pushed.Results[0].Content != update.Results[0].Content {
+		t.Error("SSE 로 받은 결과가 응답과 다름")
+	}
+
+	resp = post("/preview", map[string]string{previewTokenField: "unknown"}, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("알 수 없는 토큰 status = %d, want 404", resp.StatusCode)
+	}
+}
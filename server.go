@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 서버 설정 환경 변수
+const (
+	envHost      = "AHTVC_HOST"
+	envPort      = "AHTVC_PORT"
+	envNoBrowser = "AHTVC_NO_BROWSER"
+	envPipelines = "AHTVC_PIPELINES"
+	envDataDir   = "AHTVC_DATA_DIR"
+	envAutoEQDir = "AHTVC_AUTOEQ_DIR"
+	envLang      = "AHTVC_LANG" // 로그/콘솔 메시지 언어 (ko, en)
+)
+
+// 종료 시 진행 중인 요청을 기다리는 최대 시간
+const shutdownTimeout = 10 * time.Second
+
+// 서버 설정
+type serverConfig struct {
+	Host          string // 바인드 주소 (기본값: 127.0.0.1)
+	Port          int    // 포트 (0이면 임의 포트)
+	NoBrowser     bool   // 브라우저 자동 실행 안 함
+	PipelinesPath string // 파이프라인 정의 JSON 파일 경로 (비어 있으면 내장 정의)
+	DataDir       string // 변환 기록/프리셋 저장 폴더 (비어 있으면 사용자 설정 폴더)
+	NoHistory     bool   // 변환 기록/프리셋 저장 안 함
+	AutoEQDir     string // 기기 검색용 로컬 AutoEQ 저장소 폴더 (비어 있으면 사용 안 함)
+}
+
+// 명령행 인자와 환경 변수에서 서버 설정 읽기 (명령행 인자가 우선)
+func loadServerConfig(args []string) (serverConfig, error) {
+	cfg := serverConfig{Host: "127.0.0.1", PipelinesPath: strings.TrimSpace(os.Getenv(envPipelines)), DataDir: strings.TrimSpace(os.Getenv(envDataDir)), AutoEQDir: strings.TrimSpace(os.Getenv(envAutoEQDir))}
+	if host := strings.TrimSpace(os.Getenv(envHost)); host != "" {
+		cfg.Host = host
+	}
+	if port := strings.TrimSpace(os.Getenv(envPort)); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return cfg, errorf("%s 값이 올바르지 않습니다: '%s'", envPort, port)
+		}
+		cfg.Port = p
+	}
+	if noBrowser := strings.TrimSpace(os.Getenv(envNoBrowser)); noBrowser != "" {
+		b, err := strconv.ParseBool(noBrowser)
+		if err != nil {
+			return cfg, errorf("%s 값이 올바르지 않습니다: '%s'", envNoBrowser, noBrowser)
+		}
+		cfg.NoBrowser = b
+	}
+
+	fs := flag.NewFlagSet("ahtvc", flag.ContinueOnError)
+	fs.StringVar(&cfg.Host, "host", cfg.Host, "바인드 주소 (모든 인터페이스: 0.0.0.0, 환경 변수 "+envHost+")")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "포트 번호 (0이면 임의 포트, 환경 변수 "+envPort+")")
+	fs.BoolVar(&cfg.NoBrowser, "no-browser", cfg.NoBrowser, "브라우저를 자동으로 열지 않음 (환경 변수 "+envNoBrowser+")")
+	fs.StringVar(&cfg.PipelinesPath, "pipelines", cfg.PipelinesPath, "파이프라인 정의 JSON 파일 (환경 변수 "+envPipelines+")")
+	fs.StringVar(&cfg.DataDir, "data-dir", cfg.DataDir, "변환 기록/프리셋 저장 폴더 (기본: 사용자 설정 폴더의 ahtvc, 환경 변수 "+envDataDir+")")
+	fs.BoolVar(&cfg.NoHistory, "no-history", cfg.NoHistory, "변환 기록/프리셋을 저장하지 않음")
+	fs.StringVar(&cfg.AutoEQDir, "autoeq", cfg.AutoEQDir, "기기 검색용 로컬 AutoEQ 저장소 폴더 (환경 변수 "+envAutoEQDir+")")
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		return cfg, errorf("알 수 없는 인자: %s", strings.Join(fs.Args(), " "))
+	}
+	if cfg.Port < 0 || cfg.Port > 65535 {
+		return cfg, errorf("포트 번호가 올바르지 않습니다: %d", cfg.Port)
+	}
+	return cfg, nil
+}
+
+// 리스너 주소로 브라우저에서 열 URL 생성 (와일드카드 주소는 localhost로 대체)
+func browserURL(addr net.Addr) string {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return "http://" + addr.String()
+	}
+	host := tcpAddr.IP.String()
+	if tcpAddr.IP == nil || tcpAddr.IP.IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(tcpAddr.Port))
+}
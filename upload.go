@@ -0,0 +1,215 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"ahtvc/eq"
+)
+
+// 업로드 및 서버 제한
+const (
+	maxUploadBytes    = 2 << 20 // 요청 본문 최대 크기 (2MB)
+	maxMultipartInMem = 1 << 20 // 메모리에 보관할 멀티파트 최대 크기
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 120 * time.Second
+)
+
+// CSRF 토큰 쿠키 및 폼 필드 이름
+const (
+	csrfCookieName = "ahtvc_csrf"
+	csrfFieldName  = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+	csrfTokenBytes = 32
+)
+
+// 업로드 검증 오류
+var (
+	errBinaryUpload  = errorf("바이너리 파일은 업로드할 수 없습니다 (AutoEQ 텍스트 파일을 선택하세요)")
+	errNotUTF8Upload = errorf("UTF-8 텍스트 파일만 지원합니다 (파일 인코딩을 UTF-8로 저장해주세요)")
+	errCSRFMismatch  = errorf("보안 토큰이 없거나 일치하지 않습니다. 페이지를 새로고침한 뒤 다시 시도해주세요")
+)
+
+// 제한이 설정된 HTTP 서버 생성
+func newHTTPServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+// CSRF 토큰 쿠키 확인 및 발급 (Double Submit Cookie 방식)
+func ensureCSRFToken(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(csrfCookieName); err == nil && isValidCSRFToken(cookie.Value) {
+		return cookie.Value
+	}
+	buf := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("CSRF 토큰 생성 실패: %v", err))
+	}
+	token := hex.EncodeToString(buf)
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token
+}
+
+// 토큰 형식 확인
+func isValidCSRFToken(token string) bool {
+	decoded, err := hex.DecodeString(token)
+	return err == nil && len(decoded) == csrfTokenBytes
+}
+
+// POST 요청의 CSRF 토큰 검증 (폼 필드 또는 헤더 값이 쿠키와 같아야 함)
+func checkCSRF(r *http.Request) error {
+	cookie, err := r.Cookie(csrfCookieName)
+	if err != nil || !isValidCSRFToken(cookie.Value) {
+		return errCSRFMismatch
+	}
+	submitted := r.Header.Get(csrfHeaderName)
+	if submitted == "" {
+		submitted = r.FormValue(csrfFieldName)
+	}
+	if subtle.ConstantTimeCompare([]byte(submitted), []byte(cookie.Value)) != 1 {
+		return errCSRFMismatch
+	}
+	return nil
+}
+
+// 업로드 요청 준비: 본문 크기 제한, 멀티파트 파싱, CSRF 검증
+// 실패하면 응답 상태 코드와 사용자 메시지를 반환합니다.
+func prepareUpload(w http.ResponseWriter, r *http.Request) (int, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	if err := r.ParseMultipartForm(maxMultipartInMem); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return http.StatusRequestEntityTooLarge, errorf("업로드 크기 제한(%dMB)을 초과했습니다", maxUploadBytes>>20)
+		}
+		return http.StatusBadRequest, errorf("요청 형식 오류: %w", err)
+	}
+	if err := checkCSRF(r); err != nil {
+		return http.StatusForbidden, err
+	}
+	return http.StatusOK, nil
+}
+
+// 업로드 파일을 읽고 UTF-8 텍스트인지 검증
+func readTextUpload(file multipart.File) ([]byte, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTextContent(content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// ZIP 파일 시그니처
+var zipSignature = []byte("PK\x03\x04")
+
+// EQ 설정 업로드 읽기
+// ZIP (Equalizer APO 설정 폴더) 이면 주 설정 파일의 Include 를 펼친 텍스트와 ZIP 안의 설정 파일 경로를 반환합니다.
+func readConfigUpload(file multipart.File) (content []byte, configPath string, err error) {
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", err
+	}
+	if !bytes.HasPrefix(raw, zipSignature) {
+		if err := validateTextContent(raw); err != nil {
+			return nil, "", err
+		}
+		return raw, "", nil
+	}
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, "", errorf("ZIP 파일을 열 수 없습니다: %w", err)
+	}
+	configPath, err = findZipConfig(zr)
+	if err != nil {
+		return nil, "", err
+	}
+	expanded, err := eq.ExpandIncludes(zr, configPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := validateTextContent([]byte(expanded)); err != nil {
+		return nil, "", err
+	}
+	return []byte(expanded), configPath, nil
+}
+
+// ZIP 안의 주 설정 파일 (가장 위 폴더의 config.txt, 없으면 유일한 .txt 파일)
+func findZipConfig(zr *zip.Reader) (string, error) {
+	var configs, texts []string
+	for _, f := range zr.File {
+		name := f.Name
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		switch base := strings.ToLower(path.Base(name)); {
+		case base == "config.txt":
+			configs = append(configs, name)
+		case strings.HasSuffix(base, ".txt"):
+			texts = append(texts, name)
+		}
+	}
+	if len(configs) > 0 {
+		sort.SliceStable(configs, func(i, j int) bool {
+			return strings.Count(configs[i], "/") < strings.Count(configs[j], "/")
+		})
+		return configs[0], nil
+	}
+	if len(texts) == 1 {
+		return texts[0], nil
+	}
+	return "", errorf("ZIP 안에서 주 설정 파일(config.txt)을 찾을 수 없습니다")
+}
+
+// 텍스트 내용 검증 (NUL 바이트 포함 시 바이너리, 잘못된 UTF-8 시 거부)
+func validateTextContent(content []byte) error {
+	if bytes.HasPrefix(content, []byte{0xFF, 0xFE}) || bytes.HasPrefix(content, []byte{0xFE, 0xFF}) {
+		return errNotUTF8Upload // UTF-16 BOM
+	}
+	if bytes.IndexByte(content, 0) >= 0 {
+		return errBinaryUpload
+	}
+	if !utf8.Valid(content) {
+		return errNotUTF8Upload
+	}
+	return nil
+}
+
+// 업로드 읽기 오류의 응답 상태 코드
+func uploadErrorStatus(err error) int {
+	if errors.Is(err, errBinaryUpload) || errors.Is(err, errNotUTF8Upload) {
+		return http.StatusUnsupportedMediaType
+	}
+	var msg *eq.Message
+	if errors.As(err, &msg) {
+		return http.StatusBadRequest // 잘못된 ZIP/Include 등 사용자 입력 오류
+	}
+	return http.StatusInternalServerError
+}
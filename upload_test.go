@@ -0,0 +1,228 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testCSRFToken = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+// 멀티파트 업로드 요청 생성
+func newUploadRequest(t *testing.T, path string, files map[string][]byte, fields map[string]string, cookieToken string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		fw, err := mw.CreateFormFile(name, name+".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(content)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if cookieToken != "" {
+		req.AddCookie(&http.Cookie{Name: csrfCookieName, Value: cookieToken})
+	}
+	return req
+}
+
+func readCoreEQ(t *testing.T) []byte {
+	t.Helper()
+	content, err := os.ReadFile("AHTVC_Core-By_MiFun.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func TestHandleConvertRejections(t *testing.T) {
+	valid := readCoreEQ(t)
+	validFields := map[string]string{csrfFieldName: testCSRFToken}
+
+	tests := []struct {
+		name        string
+		files       map[string][]byte
+		fields      map[string]string
+		cookie      string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "CSRF 쿠키 없음",
+			files:       map[string][]byte{"sourceHarmanFile": valid},
+			fields:      validFields,
+			wantStatus:  http.StatusForbidden,
+			wantMessage: errCSRFMismatch.Error(),
+		},
+		{
+			name:        "CSRF 토큰 불일치",
+			files:       map[string][]byte{"sourceHarmanFile": valid},
+			fields:      map[string]string{csrfFieldName: strings.Repeat("f", 64)},
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusForbidden,
+			wantMessage: errCSRFMismatch.Error(),
+		},
+		{
+			name:        "CSRF 토큰 누락",
+			files:       map[string][]byte{"sourceHarmanFile": valid},
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusForbidden,
+			wantMessage: errCSRFMismatch.Error(),
+		},
+		{
+			name:        "크기 제한 초과",
+			files:       map[string][]byte{"sourceHarmanFile": bytes.Repeat([]byte("a"), maxUploadBytes+1)},
+			fields:      validFields,
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+			wantMessage: "업로드 크기 제한",
+		},
+		{
+			name:        "바이너리 파일",
+			files:       map[string][]byte{"sourceHarmanFile": {0x89, 'P', 'N', 'G', 0x00, 0x01}},
+			fields:      validFields,
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: errBinaryUpload.Error(),
+		},
+		{
+			name:        "UTF-8이 아닌 파일",
+			files:       map[string][]byte{"sourceHarmanFile": []byte("GraphicEQ: 20 -1.0; \xb0\xa1 \xff")},
+			fields:      validFields,
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: errNotUTF8Upload.Error(),
+		},
+		{
+			name:        "UTF-16 파일",
+			files:       map[string][]byte{"sourceHarmanFile": {0xFF, 0xFE, 'G', 0x00, 'r', 0x00}},
+			fields:      validFields,
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: errNotUTF8Upload.Error(),
+		},
+		{
+			name:        "측정값 파일이 바이너리",
+			files:       map[string][]byte{"sourceHarmanFile": valid, "rawMeasurementFile": {0x00, 0x01, 0x02}},
+			fields:      validFields,
+			cookie:      testCSRFToken,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantMessage: errBinaryUpload.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newUploadRequest(t, "/", tt.files, tt.fields, tt.cookie)
+			rec := httptest.NewRecorder()
+			handleConvert(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d\n%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantMessage) {
+				t.Errorf("응답에 %q 메시지가 없음", tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHandleConvertAcceptsValidUpload(t *testing.T) {
+	req := newUploadRequest(t, "/", map[string][]byte{"sourceHarmanFile": readCoreEQ(t)}, nil, testCSRFToken)
+	req.Header.Set(csrfHeaderName, testCSRFToken)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "GraphicEQ:") {
+		t.Error("변환 결과가 응답에 없음")
+	}
+}
+
+func TestHandleCompareRejectsWithoutCSRF(t *testing.T) {
+	core := readCoreEQ(t)
+	req := newUploadRequest(t, "/compare", map[string][]byte{"fileA": core, "fileB": core}, nil, "")
+	rec := httptest.NewRecorder()
+	handleCompare(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestGetIssuesCSRFCookie(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handleConvert(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != csrfCookieName || !isValidCSRFToken(cookies[0].Value) {
+		t.Fatalf("CSRF 쿠키가 발급되지 않음: %v", cookies)
+	}
+	if !strings.Contains(rec.Body.String(), cookies[0].Value) {
+		t.Error("폼에 CSRF 토큰이 포함되지 않음")
+	}
+}
+
+func TestNewHTTPServerTimeouts(t *testing.T) {
+	server := newHTTPServer(nil)
+	if server.ReadHeaderTimeout == 0 || server.ReadTimeout == 0 || server.WriteTimeout == 0 || server.IdleTimeout == 0 {
+		t.Errorf("서버 타임아웃이 설정되지 않음: %+v", server)
+	}
+}
+
+// Equalizer APO 설정 폴더 ZIP (Include 와 채널별 EQ)
+func TestHandleConvertAPOZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"EqualizerAPO/config/config.txt":   "Preamp: -6 dB\nChannel: L\nInclude: left.txt\nChannel: R\nInclude: right\\eq.txt\n",
+		"EqualizerAPO/config/left.txt":     string(readCoreEQ(t)),
+		"EqualizerAPO/config/right/eq.txt": "Filter 1: ON PK Fc 1000 Hz Gain -3 dB Q 1\n",
+	} {
+		fw, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	zw.Close()
+
+	silenceLogs(t)
+	req := newUploadRequest(t, "/?format=json", map[string][]byte{"sourceHarmanFile": buf.Bytes()}, map[string]string{csrfFieldName: testCSRFToken}, testCSRFToken)
+	rec := httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	for _, want := range []string{`"name":"Result 1 (L)"`, `"name":"Result 1 (R)"`, "sourceHarmanFile (R)_AHTVC-By_MiFun.txt", "channel=L", "GraphicEQ (L, R)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("%q 없음", want)
+		}
+	}
+
+	// 설정 파일을 찾을 수 없는 ZIP 은 400
+	buf.Reset()
+	zw = zip.NewWriter(&buf)
+	zw.Create("a.txt")
+	zw.Create("b.txt")
+	zw.Close()
+	req = newUploadRequest(t, "/", map[string][]byte{"sourceHarmanFile": buf.Bytes()}, map[string]string{csrfFieldName: testCSRFToken}, testCSRFToken)
+	rec = httptest.NewRecorder()
+	handleConvert(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"ahtvc/eq"
+)
+
+// 폴더 감시 기본값
+const (
+	defaultWatchInterval = 5 * time.Second
+	watchStateFilename   = ".ahtvc-watch-state.json"
+)
+
+// 처리한 파일 기록
+type watchEntry struct {
+	Hash        string    `json:"hash"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"modTime"`
+	Outputs     []string  `json:"outputs,omitempty"`
+	Skipped     string    `json:"skipped,omitempty"`
+	ProcessedAt time.Time `json:"processedAt"`
+}
+
+// 폴더 감시 상태 (입력 파일 상대 경로 -> 처리 기록)
+type watchState struct {
+	Files map[string]watchEntry `json:"files"`
+}
+
+// 폴더 감시 설정
+type watcher struct {
+	inDir     string
+	outDir    string
+	statePath string
+	pipeline  eq.PipelineConfig
+	opts      convertOptions // Exports 가 켜져 있으면 앱별 내보내기 파일도 저장
+	state     watchState
+}
+
+// CLI 폴더 감시 모드: ahtvc watch -in DIR -out DIR [-interval 5s] [-pipeline name] [-pipelines file] [-exports]
+func runWatchCLI(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	inDir := fs.String("in", "", "감시할 입력 폴더 (AutoEQ .txt 파일)")
+	outDir := fs.String("out", "", "변환 결과를 저장할 폴더")
+	interval := fs.Duration("interval", defaultWatchInterval, "폴더 확인 주기")
+	pipelineName := fs.String("pipeline", "", "사용할 파이프라인 이름 (비어 있으면 첫 번째)")
+	pipelinesPath := fs.String("pipelines", strings.TrimSpace(os.Getenv(envPipelines)), "파이프라인 정의 JSON 파일 (환경 변수 "+envPipelines+")")
+	exports := fs.Bool("exports", false, "앱별 내보내기 파일도 함께 저장")
+	once := fs.Bool("once", false, "한 번만 확인하고 종료")
+	preampMode := fs.String("preamp-mode", "", "Preamp 계산 방식 (sampled, interpolated; 비어 있으면 파이프라인 설정)")
+	headroom := fs.Float64("headroom", 0, "Preamp 추가 여유 (dB)")
+	separatePreamp := fs.Bool("separate-preamp", false, "게인을 내리는 대신 별도 Preamp 라인으로 출력")
+	levelMatch := fs.Bool("level-match", false, "출력 간 체감 음량 (ITU-R 468) 맞춤")
+	loudnessRef := fs.Float64("loudness-ref", eq.DefaultReferencePhon, "등청감 보정 기준 음량 (phon)")
+	loudnessLevels := fs.String("loudness-levels", "", "등청감 보정 청취 음량 목록 (phon, 쉼표로 구분, 예: 60,40)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *inDir == "" || *outDir == "" || fs.NArg() > 0 {
+		stderrPrintf("사용법: ahtvc watch -in DIR -out DIR [-interval 5s] [-pipeline name] [-pipelines file] [-exports] [-once] [-preamp-mode interpolated] [-headroom 1] [-separate-preamp] [-level-match] [-loudness-ref 80] [-loudness-levels 60,40]")
+		return 2
+	}
+	if *interval <= 0 {
+		stderrPrintf("확인 주기가 올바르지 않습니다: %v", *interval)
+		return 2
+	}
+
+	pipeline, err := findPipeline(*pipelinesPath, *pipelineName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, logLang.Err(err))
+		return 1
+	}
+	loudness, err := parseLoudnessOptions(strconv.FormatFloat(*loudnessRef, 'f', -1, 64), *loudnessLevels)
+	if err != nil {
+		stderrPrintf("등청감 보정 옵션 오류: %v", err)
+		return 2
+	}
+	if loudness != nil {
+		pipeline = pipeline.LoudnessFamily(loudness.Reference, loudness.Levels)
+	}
+
+	opts := convertOptions{Format: eq.DefaultFormatOptions, LevelMatch: *levelMatch, Exports: *exports}
+	opts.Format.SeparatePreamp = *separatePreamp
+	if *preampMode != "" || *headroom != 0 {
+		mode, err := eq.ParsePreampMode(*preampMode)
+		if err == nil {
+			opts.Preamp = &eq.PreampOptions{Mode: mode, Headroom: *headroom}
+			err = opts.Preamp.Validate()
+		}
+		if err != nil {
+			stderrPrintf("Preamp 옵션 오류: %v", err)
+			return 2
+		}
+	}
+
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		stderrPrintf("출력 폴더 생성 오류: %v", err)
+		return 1
+	}
+	wt := &watcher{
+		inDir:     *inDir,
+		outDir:    *outDir,
+		statePath: filepath.Join(*outDir, watchStateFilename),
+		pipeline:  pipeline,
+		opts:      opts,
+	}
+	if err := wt.loadState(); err != nil {
+		stderrPrintf("감시 상태 파일 오류 (%s): %v", wt.statePath, err)
+		return 1
+	}
+
+	logPrintf("폴더 감시 시작: %s -> %s (파이프라인 '%s', 주기 %v)\n", wt.inDir, wt.outDir, pipeline.Name, *interval)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if err := wt.scan(); err != nil {
+			logPrintf("폴더 확인 오류: %v\n", err)
+		}
+		if *once {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			logPrintf("폴더 감시 종료")
+			return 0
+		case <-ticker.C:
+		}
+	}
+}
+
+// 상태 파일 읽기 (없으면 빈 상태)
+func (wt *watcher) loadState() error {
+	wt.state = watchState{Files: map[string]watchEntry{}}
+	if err := readJSONFile(wt.statePath, &wt.state); err != nil {
+		return err
+	}
+	if wt.state.Files == nil {
+		wt.state.Files = map[string]watchEntry{}
+	}
+	return nil
+}
+
+// 상태 파일 저장 (임시 파일에 쓴 뒤 교체)
+func (wt *watcher) saveState() error {
+	return writeJSONFile(wt.statePath, wt.state)
+}
+
+// 감시 대상 확장자 (GraphicEQ .txt 및 AutoEQ .csv)
+func isWatchedFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".txt" || ext == ".csv"
+}
+
+// 입력 폴더의 .txt/.csv 파일을 확인하고 새 파일이나 바뀐 파일만 변환
+func (wt *watcher) scan() error {
+	entries, err := os.ReadDir(wt.inDir)
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	// 입력과 출력 폴더가 같을 때 직접 만든 파일은 다시 변환하지 않음
+	produced := map[string]bool{}
+	for _, record := range wt.state.Files {
+		for _, name := range record.Outputs {
+			produced[name] = true
+		}
+	}
+
+	changed := false
+	for _, entry := range entries {
+		if entry.IsDir() || !isWatchedFile(entry.Name()) || produced[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // 확인 중 삭제된 파일
+		}
+		prev, seen := wt.state.Files[entry.Name()]
+		// 크기와 수정 시각이 그대로면 다시 해시하지 않음
+		if seen && prev.Size == info.Size() && prev.ModTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		path := filepath.Join(wt.inDir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			logPrintf("파일 읽기 오류 (%s): %v\n", path, err)
+			continue
+		}
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])
+		record := watchEntry{Hash: hash, Size: info.Size(), ModTime: info.ModTime(), ProcessedAt: time.Now()}
+		if seen && prev.Hash == hash {
+			// 내용은 같고 시각만 바뀐 경우 기록만 갱신
+			record.Outputs, record.Skipped, record.ProcessedAt = prev.Outputs, prev.Skipped, prev.ProcessedAt
+		} else {
+			outputs, skipped, err := wt.convertFile(entry.Name(), content)
+			if err != nil {
+				// 실패도 기록해 두고 파일이 바뀌면 다시 시도
+				logPrintf("변환 실패 (%s): %v\n", entry.Name(), err)
+				skipped = logLang.T("변환 실패: %v", err)
+			}
+			record.Outputs, record.Skipped = outputs, skipped
+		}
+		wt.state.Files[entry.Name()] = record
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return wt.saveState()
+}
+
+// 파일 하나를 변환해 출력 폴더에 저장
+// 건너뛴 경우 이유를 반환합니다.
+func (wt *watcher) convertFile(filename string, content []byte) (outputs []string, skipped string, err error) {
+	if err := validateTextContent(content); err != nil {
+		return nil, "", err
+	}
+	input, prov, err := eq.ParseWithProvenance(string(content))
+	if err != nil {
+		return nil, "", err
+	}
+	// AHTVC 출력 파일 (입력과 출력 폴더가 같을 때 재변환 방지)
+	if prov.Generator != "" {
+		logPrintf("건너뜀 (%s): 이미 %s 출력 파일입니다\n", filename, prov.Generator)
+		return nil, logLang.T("AHTVC 출력 파일"), nil
+	}
+
+	results, err := convertSource(convertInput{
+		Source:         input.Curve,
+		Channels:       input.Channels,
+		SourceFilename: filename,
+		Target:         eq.DetectTarget(filename, string(content)),
+	}, wt.pipeline, wt.opts)
+	if err != nil {
+		return nil, "", err
+	}
+	outputs, err = writeConvertResults(wt.outDir, results)
+	if err != nil {
+		return outputs, "", err
+	}
+	logPrintf("변환 완료 (%s, %s): %s\n", filename, input.Description(), strings.Join(outputs, ", "))
+	return outputs, "", nil
+}
+
+// 변환 결과 (및 생성된 앱별 내보내기 파일) 를 폴더에 저장하고 저장한 파일 이름을 반환
+func writeConvertResults(outDir string, results []convertResult) ([]string, error) {
+	var outputs []string
+	for _, result := range results {
+		files := append([]eq.ExportFile{{Filename: result.Filename, Content: result.Content}}, result.Exports...)
+		for _, file := range files {
+			path := filepath.Join(outDir, file.Filename)
+			if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+				return outputs, err
+			}
+			outputs = append(outputs, file.Filename)
+		}
+	}
+	return outputs, nil
+}
+
+// 파이프라인 정의 파일 (비어 있으면 내장 정의) 에서 이름으로 파이프라인 찾기 (CLI 공용)
+func findPipeline(pipelinesPath, name string) (eq.PipelineConfig, error) {
+	pipelines := eq.DefaultConfig()
+	if pipelinesPath != "" {
+		var err error
+		pipelines, err = eq.LoadConfigFile(pipelinesPath)
+		if err != nil {
+			return eq.PipelineConfig{}, errorf("파이프라인 정의 파일 오류 (%s): %w", pipelinesPath, err)
+		}
+	}
+	pipeline, found := pipelines.Find(name)
+	if !found {
+		return eq.PipelineConfig{}, errorf("알 수 없는 파이프라인: '%s'", name)
+	}
+	return pipeline, nil
+}